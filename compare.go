@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
 	"flag"
 	"fmt"
@@ -11,19 +12,31 @@ import (
 
 func main() {
 	// reading the arguments
-	var one, two string
+	var one, two, gitRepo, gitPath, writeBaseline, lint string
+
+	var diffs, schema bool
 
 	// gathering the desired options
 	opt := &c.ComparisonOptions{}
 
 	flag.StringVar(&one, "one", "",
-		"required: the path to the first file to compare; must be a JSON file, or XML with the -xml option")
+		"required: the path to the first file to compare; must be a JSON file, or XML with the -xml option; can also be a folder, or a zip, tar or tar.gz archive")
 	flag.StringVar(&two, "two", "",
 		"required: the path to the second file to compare; must be of the same first file's type")
+	flag.BoolVar(&diffs, "diffs", false,
+		"if true, then -one and -two are the JSON outputs of 2 previous comparisons, and we report which differences are fixed, new, or changed")
+	flag.StringVar(&gitRepo, "git", "",
+		"the path to a local git repository; if specified, then -one and -two are revisions (commits, tags, branches...) of this repository, whose files are compared without checking them out")
+	flag.StringVar(&gitPath, "path", "",
+		"with the -git option: the path, relative to the repository's root, of the folder (or file) to compare between the 2 revisions")
 	flag.BoolVar(&opt.IsXml, "xml", false,
-		"use this option if the files are XML files")
+		"use this option if the files are XML files; without it, the type of each file is detected from its content, which allows for comparing an XML file with a JSON file")
 	flag.StringVar(&opt.IdParamsString, "idparams", "",
-		"a JSON representation of a IdentificationParameter parameter; see the docs for an example; can be the path to an existing JSON file")
+		"a JSON representation of a IdentificationParameter parameter; see the docs for an example; can be the path to an existing JSON, JSONC (JSON with comments) or YAML file")
+	flag.BoolVar(&schema, "schema", false,
+		"if true, then the JSON schema of the ID params is output")
+	flag.StringVar(&lint, "lint", "",
+		"the path to a sample file; if specified, then the ID params are checked against this file, and the '_for' paths that are never reached are reported")
 	// flag.StringVar(&opt.Outdir, "outdir", "",
 	// 	"when specified, the result is written out as a JSON into this specified output directory")
 	flag.BoolVar(&opt.Fast, "fast", false,
@@ -35,24 +48,110 @@ func main() {
 	flag.BoolVar(&opt.Check, "check", false,
 		"if true, then the ID params are output to allow for some checks")
 	flag.StringVar(&opt.IgnoredString, "ignore", "",
-		"the files to ignore, separated by a comma")
+		"the files or folders to ignore, by name or relative path, separated by a comma")
 	flag.BoolVar(&opt.AllowRaw, "allowRaw", false,
 		"if true, then it's allowed to display the raw JSON entities as difference, when added or removed; else, a display template is required")
+	flag.BoolVar(&opt.PairCompressed, "pairCompressed", false,
+		"if true, then, when comparing folders, a compressed file (gzip, zstd, bzip2) can be compared with its uncompressed version, e.g. 'foo.json.gz' with 'foo.json'")
+	flag.StringVar(&opt.PairingString, "pairing", "",
+		"a JSON representation of a PairingParameter, to pair files with different names when comparing folders (explicit 'renames', regexp 'keys', content 'similarity'); can be the path to an existing JSON file")
+	flag.IntVar(&opt.SummaryTop, "summary", 0,
+		"if > 0, then, when comparing folders, an aggregated report is output instead of the differences: totals, and this number of top offending paths (normalized with the ID params)")
+	flag.StringVar(&opt.BaselineFile, "baseline", "",
+		"the path to a baseline file, listing known, accepted differences, which are then not reported; the baseline entries that no longer occur are reported under the '_unused_baseline_' key")
+	flag.StringVar(&writeBaseline, "write-baseline", "",
+		"the path of a baseline file to write, accepting all the differences found by this comparison (and the differences of the current baseline that still occur)")
+	flag.StringVar(&opt.XmlnsString, "xmlns", "",
+		"the canonical prefixes to use for the namespace URIs, as 'prefix=uri' separated by commas (e.g. 'o=urn:orders'), or a file listing them")
+	flag.StringVar(&opt.XmlSpace, "xmlSpace", "",
+		"how to handle the whitespace in the XML text nodes: 'trim' (by default), 'collapse' or 'preserve'; can also be set per path in the ID params")
+	flag.BoolVar(&opt.XmlComments, "xmlComments", false,
+		"if true, then the XML comments are compared too, as '#comment'; can also be set per path in the ID params")
+	flag.BoolVar(&opt.XmlPIs, "xmlPIs", false,
+		"if true, then the XML processing instructions are compared too, as '#pi'; can also be set per path in the ID params")
+	flag.BoolVar(&opt.XmlMixed, "xmlMixed", false,
+		"if true, then the XML text mixed with child elements is kept, in order, as '#mixed' (e.g. 'Hello <b/> !'); can also be set per path in the ID params")
+	flag.StringVar(&opt.XmlAttrPrefix, "xmlAttrPrefix", "",
+		"the prefix of the XML attributes' names, e.g. '-' or '_' ('@' by default)")
+	flag.StringVar(&opt.XmlTextKey, "xmlTextKey", "",
+		"the key of the text of the XML elements having attributes or children, e.g. 'value' ('#text' by default)")
+	flag.BoolVar(&opt.XmlAttrsAsChildren, "xmlAttrsAsChildren", false,
+		"if true, then the XML attributes are decoded like child elements, without prefix, so that <a id=\"1\"/> equals <a><id>1</id></a>")
+	flag.StringVar(&opt.RepeatableString, "repeatable", "",
+		"for XML files: the path to an XSD, or a comma-separated list of element paths (e.g. 'order.line') - or a file listing them - so that these elements are always decoded as arrays, even when single")
+	flag.StringVar(&opt.NormalizationString, "normalize", "",
+		"how to normalize all the strings before comparing them, or building keys with them, as a JSON - or the path to a JSON file - e.g. '{\"unicode\": \"NFC\", \"whitespace\": \"trim\", \"case\": \"fold\", \"replace\": [{\"pattern\": \"^0+\", \"with\": \"\"}]}'; can also be set per path in the ID params")
+	flag.BoolVar(&opt.Embedded, "embedded", false,
+		"if true, then the strings holding JSON or XML documents - possibly encoded in base64 - are decoded, and compared as data trees; can also be set per path in the ID params")
+	flag.BoolVar(&opt.UseNumber, "useNumber", false,
+		"if true, then the numbers of the JSON files are decoded and compared exactly, rather than as float64, which silently rounds the IDs above 2^53, or the precise amounts")
+	flag.StringVar(&opt.NullPolicy, "nulls", "",
+		"how to handle the null, empty or missing values: 'strict' to distinguish a null value from a missing one, 'null-equals-missing' (by default), "+
+			"or 'empty-equals-missing' to also consider the empty strings, arrays and objects as missing; can also be set per path in the ID params")
+	flag.BoolVar(&opt.Lenient, "lenient", false,
+		"if true, then the values are compared leniently: numeric strings as numbers (e.g. '12.50' = 12.5), 'true' / 'false' as booleans, and empty strings as null; can also be set per path in the ID params")
+	flag.StringVar(&opt.Explain, "explain", "",
+		"the path of an ID param (e.g. 'data.vehicule'), or '*' for all, for which we explain, on the standard error, how the keys of the array elements are built")
 	//nolint:revive,gomnd
 	flag.IntVar(&opt.NParallel, "nparallel", 10,
 		"the number of routines used at the same time when comparing several files at once (i.e. comparing folders)")
 
 	flag.Parse()
 
+	// outputting the schema of the ID params
+	if schema {
+		fmt.Println(string(c.IdParamsSchema()))
+
+		return // we're out
+	}
+
+	// checking the ID params against a sample file
+	if lint != "" {
+		opt.SetDefaultLogger().Resolve()
+
+		problems, errLint := opt.LintIdParams(lint)
+		if errLint != nil {
+			panic(fmt.Errorf("Could not lint the ID params. Cause: %s", errLint))
+		}
+
+		doJsonOutput(problems, "the lint problems")
+
+		if len(problems) > 0 {
+			os.Exit(1)
+		}
+
+		return // we're out
+	}
+
 	// controlling the presence of 2 things to compare
 	if one == "" || two == "" {
 		flag.PrintDefaults()
 		os.Exit(1)
 	}
 
+	// comparing the results of 2 comparisons
+	if diffs {
+		comparison, errComp := c.CompareComparisons(one, two, opt.SetDefaultLogger())
+		if errComp != nil {
+			panic(fmt.Errorf("Could not compare the comparisons. Cause: %s", errComp))
+		}
+
+		doJsonOutput(comparison, "the comparison of the comparisons")
+
+		return // we're out
+	}
+
+	// a summary does not list the differences
+	if writeBaseline != "" && opt.SummaryTop > 0 {
+		panic("Cannot write a baseline from a summary (-summary option)")
+	}
+
 	// let's set a logger, and "finalize" the options
 	opt.SetDefaultLogger().Resolve()
 
+	// explaining how the keys have been built, even if the comparison fails
+	defer opt.WriteExplanations(os.Stderr)
+
 	// are we just performing a check ?
 	if opt.Check {
 		doJsonOutput(opt.GetIdParams(), "the ID params")
@@ -60,21 +159,33 @@ func main() {
 		return // we're out
 	}
 
-	// checking the nature of the inputs
+	// the comparison result
+	var comparison c.Comparison
+
+	var errComp error
+
+	// comparing 2 revisions of a git repository
+	if gitRepo != "" {
+		if comparison, errComp = c.CompareGitRevisions(gitRepo, one, two, gitPath, opt); errComp != nil {
+			panic(fmt.Errorf("Could not perform the comparison. Cause: %s", errComp))
+		}
+
+		doBaselineOutput(opt, comparison, true, writeBaseline)
+		doJsonOutput(comparison, "the comparison")
+
+		return // we're out
+	}
+
+	// checking the nature of the inputs - archives are compared like folders
 	//nolint:ifshort
-	oneDir := isDirectory(one)
+	oneDir := isDirectory(one) || c.IsArchive(one)
 	//nolint:ifshort
-	twoDir := isDirectory(two)
+	twoDir := isDirectory(two) || c.IsArchive(two)
 
 	if oneDir != twoDir {
 		panic(fmt.Errorf("Cannot compare a file to a directory (one is directory: %t; two is a directory: %t)", oneDir, twoDir))
 	}
 
-	// the comparison result
-	var comparison c.Comparison
-
-	var errComp error
-
 	// comparing 2 files, or 2 folders
 	if !oneDir {
 		comparison, errComp = c.CompareFiles(one, two, opt, true)
@@ -87,6 +198,7 @@ func main() {
 	}
 
 	// outputting the comparison
+	doBaselineOutput(opt, comparison, oneDir, writeBaseline)
 	doJsonOutput(comparison, "the comparison")
 }
 
@@ -100,16 +212,32 @@ func isDirectory(path string) bool {
 	return fileInfo.IsDir()
 }
 
+// writing out the baseline, if required
+func doBaselineOutput(opt *c.ComparisonOptions, comparison c.Comparison, folders bool, baselinePath string) {
+	if baselinePath == "" {
+		return
+	}
+
+	if errWrite := opt.WriteBaseline(comparison, folders, baselinePath); errWrite != nil {
+		panic(errWrite)
+	}
+}
+
 // outputting an object
 func doJsonOutput(object interface{}, what string) {
-	// JSON-marshaling it
-	objectBytes, errMarsh := json.MarshalIndent(object, "", "	")
-	if errMarsh != nil {
+	// JSON-marshaling it, without escaping characters like '>', which are common in our paths and file keys
+	var objectBytes bytes.Buffer
+
+	encoder := json.NewEncoder(&objectBytes)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "	")
+
+	if errMarsh := encoder.Encode(object); errMarsh != nil {
 		panic(fmt.Errorf("Error while JSON-marshaling %s. Cause: %s", what, errMarsh))
 	}
 
 	// outputting it
-	if _, errWrite := os.Stdout.Write(objectBytes); errWrite != nil {
+	if _, errWrite := os.Stdout.Write(objectBytes.Bytes()); errWrite != nil {
 		panic(fmt.Errorf("Error while writing out %s. Cause: %s", what, errWrite))
 	}
 }
@@ -0,0 +1,212 @@
+package core
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+//------------------------------------------------------------------------------
+// Here we handle archives (zip, tar, tar.gz), that can be seen as folders
+//------------------------------------------------------------------------------
+
+// the types of archives we can read
+type archiveType string
+
+const (
+	archiveTypeNONE  archiveType = ""
+	archiveTypeZIP   archiveType = "zip"
+	archiveTypeTAR   archiveType = "tar"
+	archiveTypeTARGZ archiveType = "tar.gz"
+)
+
+// getArchiveType determines the type of archive from a file's extension
+func getArchiveType(filepath string) archiveType {
+	lowerPath := strings.ToLower(filepath)
+
+	switch {
+	case strings.HasSuffix(lowerPath, ".zip"):
+		return archiveTypeZIP
+	case strings.HasSuffix(lowerPath, ".tar"):
+		return archiveTypeTAR
+	case strings.HasSuffix(lowerPath, ".tar.gz"), strings.HasSuffix(lowerPath, ".tgz"):
+		return archiveTypeTARGZ
+	}
+
+	return archiveTypeNONE
+}
+
+// IsArchive tells if the given path points to an archive that can be compared as if it were a folder
+func IsArchive(filepath string) bool {
+	return getArchiveType(filepath) != archiveTypeNONE
+}
+
+// newSource returns the right source for the given path: a folder, or an archive
+func newSource(filepath string) fileSource {
+	if archType := getArchiveType(filepath); archType != archiveTypeNONE {
+		return &archiveSource{path: filepath, archType: archType}
+	}
+
+	return &folderSource{path: filepath}
+}
+
+//------------------------------------------------------------------------------
+// An archive, seen as a folder
+//------------------------------------------------------------------------------
+
+type archiveSource struct {
+	path     string            // the path to the archive file
+	archType archiveType       // the type of archive
+	entries  map[string][]byte // the content of the archive's files, loaded in memory, by entry path
+}
+
+func (thisArchive *archiveSource) getPath() string {
+	return thisArchive.path
+}
+
+// listing the archive's entries - which are all read in memory at this point
+func (thisArchive *archiveSource) listFiles(options *ComparisonOptions) ([]string, error) {
+	if errLoad := thisArchive.load(); errLoad != nil {
+		return nil, errLoad
+	}
+
+	filesSlice := []string{}
+
+	for entryName := range thisArchive.entries {
+		if !options.isIgnored(entryName) {
+			filesSlice = append(filesSlice, entryName)
+		}
+	}
+
+	// let's sort the file names
+	sort.Strings(filesSlice)
+
+	return filesSlice, nil
+}
+
+func (thisArchive *archiveSource) readFile(name string) ([]byte, error) {
+	entryBytes, exists := thisArchive.entries[name]
+	if !exists {
+		return nil, fmt.Errorf("No entry '%s' in archive '%s'", name, thisArchive.path)
+	}
+
+	return entryBytes, nil
+}
+
+// load reads all the archive's entries, once
+func (thisArchive *archiveSource) load() error {
+	if thisArchive.entries != nil {
+		return nil
+	}
+
+	thisArchive.entries = map[string][]byte{}
+
+	var errLoad error
+
+	switch thisArchive.archType {
+	case archiveTypeZIP:
+		errLoad = thisArchive.loadZip()
+	case archiveTypeTAR, archiveTypeTARGZ:
+		errLoad = thisArchive.loadTar()
+	default:
+		errLoad = fmt.Errorf("Unhandled archive type: '%s'", thisArchive.archType)
+	}
+
+	if errLoad != nil {
+		return fmt.Errorf("Error while reading archive '%s'. Cause: %s", thisArchive.path, errLoad)
+	}
+
+	return nil
+}
+
+func (thisArchive *archiveSource) loadZip() error {
+	zipReader, errOpen := zip.OpenReader(thisArchive.path)
+	if errOpen != nil {
+		return errOpen
+	}
+
+	defer zipReader.Close()
+
+	for _, zipFile := range zipReader.File {
+		// we're only interested in the files here
+		if zipFile.FileInfo().IsDir() {
+			continue
+		}
+
+		entryReader, errEntry := zipFile.Open()
+		if errEntry != nil {
+			return fmt.Errorf("cannot open entry '%s' (%s)", zipFile.Name, errEntry)
+		}
+
+		entryBytes, errRead := io.ReadAll(entryReader)
+		entryReader.Close()
+
+		if errRead != nil {
+			return fmt.Errorf("cannot read entry '%s' (%s)", zipFile.Name, errRead)
+		}
+
+		thisArchive.entries[cleanEntryName(zipFile.Name)] = entryBytes
+	}
+
+	return nil
+}
+
+func (thisArchive *archiveSource) loadTar() error {
+	archiveBytes, errRead := os.ReadFile(thisArchive.path)
+	if errRead != nil {
+		return errRead
+	}
+
+	var reader io.Reader = bytes.NewReader(archiveBytes)
+
+	// a tarball might have been gzipped
+	if thisArchive.archType == archiveTypeTARGZ {
+		gzipReader, errGzip := gzip.NewReader(reader)
+		if errGzip != nil {
+			return errGzip
+		}
+
+		defer gzipReader.Close()
+
+		reader = gzipReader
+	}
+
+	tarReader := tar.NewReader(reader)
+
+	for {
+		header, errNext := tarReader.Next()
+		if errNext == io.EOF {
+			break
+		}
+
+		if errNext != nil {
+			return errNext
+		}
+
+		// we're only interested in the regular files here
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		entryBytes, errEntry := io.ReadAll(tarReader)
+		if errEntry != nil {
+			return fmt.Errorf("cannot read entry '%s' (%s)", header.Name, errEntry)
+		}
+
+		thisArchive.entries[cleanEntryName(header.Name)] = entryBytes
+	}
+
+	return nil
+}
+
+// cleanEntryName normalizes an entry's path, like "./folder/file.json" into "folder/file.json"
+func cleanEntryName(name string) string {
+	return strings.TrimPrefix(path.Clean("/"+name), "/")
+}
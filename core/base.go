@@ -1,5 +1,7 @@
 package core
 
+import "sort"
+
 //------------------------------------------------------------------------------
 // Here is the base for comparing stuff
 //------------------------------------------------------------------------------
@@ -16,15 +18,91 @@ func nodif() Comparison {
 }
 
 func one(obj interface{}) Comparison {
-	return Comparison{"_del_": obj}
+	return Comparison{markerDEL: obj}
 }
 
 func two(obj interface{}) Comparison {
-	return Comparison{"_new_": obj}
+	return Comparison{markerNEW: obj}
 }
 
 func one_two(obj1, obj2 interface{}) Comparison {
-	return Comparison{"_one_": obj1, "_two_": obj2}
+	return Comparison{markerONE: obj1, markerTWO: obj2}
+}
+
+func coerced(obj1, obj2 interface{}) Comparison {
+	return Comparison{markerONE: obj1, markerTWO: obj2, markerCOERCED: true}
+}
+
+// the markers used in the comparisons to signal the differences
+const (
+	markerDEL     = "_del_"     // something only exists in the first object
+	markerNEW     = "_new_"     // something only exists in the second object
+	markerONE     = "_one_"     // the value in the first object...
+	markerTWO     = "_two_"     // ... VS the value in the second object
+	markerMOVED   = "_moved_"   // an array element has changed position
+	markerCOERCED = "_coerced_" // the values in the 2 objects are only equal once coerced, with the lenient comparisons
+)
+
+// the markers used as values in the comparisons, with the strict policy for the null values, since a null value cannot be told from a missing one
+// once written out as JSON
+const (
+	markerNULL    = "_null_"    // the property is present, with a null value
+	markerMISSING = "_missing_" // the property is absent
+)
+
+// the kinds of differences
+const (
+	diffREMOVED  = "removed"
+	diffADDED    = "added"
+	diffMODIFIED = "modified"
+	diffMOVED    = "moved"
+	diffCOERCED  = "coerced"
+	diffNULL     = "null"
+)
+
+// getDiffKind tells if the given node is a difference, i.e. a leaf of a comparison, and which kind of difference
+func getDiffKind(node map[string]interface{}) (string, bool) {
+	if _, isCoerced := node[markerCOERCED]; isCoerced {
+		return diffCOERCED, true
+	}
+
+	value1, isOne := node[markerONE]
+	value2, isTwo := node[markerTWO]
+
+	if isOne || isTwo {
+		if isNullMarker(value1) || isNullMarker(value2) {
+			return diffNULL, true
+		}
+
+		return diffMODIFIED, true
+	}
+
+	if _, isDel := node[markerDEL]; isDel {
+		return diffREMOVED, true
+	}
+
+	if _, isNew := node[markerNEW]; isNew {
+		return diffADDED, true
+	}
+
+	return "", false
+}
+
+// isNullMarker tells if the given value of a comparison stands for a null or a missing value
+func isNullMarker(value interface{}) bool {
+	return value == markerNULL || value == markerMISSING
+}
+
+// asMap returns the given comparison node as a map - be it a Comparison, or a map coming from a JSON unmarshalling
+func asMap(node interface{}) (map[string]interface{}, bool) {
+	switch node := node.(type) {
+	case Comparison:
+		return node, true
+	case map[string]interface{}:
+		return node, true
+	}
+
+	return nil, false
 }
 
 //------------------------------------------------------------------------------
@@ -38,3 +116,33 @@ const (
 	FileTypeJSON FileType = "JSON"
 	FileTypeXML  FileType = "XML"
 )
+
+// walkDiffs recursively calls the given function on each difference (i.e. leaf) found in the given comparison node, along with its path
+func walkDiffs(node map[string]interface{}, currentPath string, visit func(diffPath, kind string, diff map[string]interface{})) {
+	if kind, isDiff := getDiffKind(node); isDiff {
+		visit(currentPath, kind, node)
+
+		return
+	}
+
+	// let's always walk in the same order
+	keys := []string{}
+	for key := range node {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if child, isMap := asMap(node[key]); isMap {
+			// a move is a difference too
+			if key == markerMOVED {
+				visit(currentPath+">"+key, diffMOVED, child)
+
+				continue
+			}
+
+			walkDiffs(child, currentPath+">"+key, visit)
+		}
+	}
+}
@@ -0,0 +1,324 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"reflect"
+	"regexp"
+	"sort"
+	"sync"
+)
+
+//------------------------------------------------------------------------------
+// Here we handle baselines, i.e. lists of known, accepted differences, that
+// are removed from the comparisons
+//------------------------------------------------------------------------------
+
+// the key under which the baseline entries that have not been used are reported
+const baselineUNUSED = "_unused_baseline_"
+
+// Baseline : a list of known, accepted differences
+type Baseline struct {
+	Entries []*BaselineEntry `json:"entries"`
+
+	// technical properties
+	mx *sync.Mutex
+}
+
+// BaselineEntry : an accepted difference
+type BaselineEntry struct {
+	File    string      `json:"file,omitempty"`    // the file (or pair of files) where the difference occurs - can be a glob pattern; empty when comparing 2 files
+	Path    string      `json:"path"`              // the path to the difference, e.g. ">data>vehicule>ABC123>price"
+	Kind    string      `json:"kind,omitempty"`    // if specified, the kind of difference: modified, added, removed, moved, coerced, or null
+	One     interface{} `json:"one,omitempty"`     // if specified, the accepted value in the first file
+	Two     interface{} `json:"two,omitempty"`     // if specified, the accepted value in the second file
+	Pattern string      `json:"pattern,omitempty"` // if specified, a regular expression that the values must match
+
+	// technical properties
+	patternRegexp *regexp.Regexp
+	used          bool
+}
+
+// ReadBaseline reads a baseline from the given JSON file
+func ReadBaseline(filepath string) (*Baseline, error) {
+	fileBytes, errRead := os.ReadFile(filepath)
+	if errRead != nil {
+		return nil, fmt.Errorf("Error while reading the baseline file '%s'. Cause: %s", filepath, errRead)
+	}
+
+	baseline := &Baseline{}
+
+	if errUnmarsh := json.Unmarshal(fileBytes, baseline); errUnmarsh != nil {
+		return nil, fmt.Errorf("Error while unmarshalling the baseline file '%s'. Cause: %s", filepath, errUnmarsh)
+	}
+
+	if errResolve := baseline.Resolve(); errResolve != nil {
+		return nil, fmt.Errorf("Invalid baseline file '%s'. Cause: %s", filepath, errResolve)
+	}
+
+	return baseline, nil
+}
+
+// Resolve checks this baseline, and prepares its entries for use
+func (thisBaseline *Baseline) Resolve() error {
+	thisBaseline.mx = new(sync.Mutex)
+
+	for _, entry := range thisBaseline.Entries {
+		if entry.Pattern != "" {
+			var errCompile error
+			if entry.patternRegexp, errCompile = regexp.Compile(entry.Pattern); errCompile != nil {
+				return fmt.Errorf("invalid pattern '%s' for path '%s': %s", entry.Pattern, entry.Path, errCompile)
+			}
+		}
+	}
+
+	return nil
+}
+
+// BuildBaseline builds a baseline accepting all the differences of the given comparison; if it's a comparison of folders,
+// then its first level keys are considered as file keys
+func BuildBaseline(comparison Comparison, folders bool) *Baseline {
+	baseline := &Baseline{Entries: []*BaselineEntry{}}
+
+	addEntries := func(file string, node map[string]interface{}) {
+		walkDiffs(node, "", func(diffPath, kind string, diff map[string]interface{}) {
+			entry := &BaselineEntry{File: file, Path: diffPath, Kind: kind}
+
+			switch kind {
+			case diffMODIFIED, diffCOERCED, diffNULL:
+				entry.One, entry.Two = diff[markerONE], diff[markerTWO]
+			case diffREMOVED:
+				entry.One = diff[markerDEL]
+			case diffADDED:
+				entry.Two = diff[markerNEW]
+			case diffMOVED:
+				entry.One, entry.Two = diff[moveFROM], diff[moveTO]
+			}
+
+			baseline.Entries = append(baseline.Entries, entry)
+		})
+	}
+
+	if !folders {
+		addEntries("", comparison)
+
+		return baseline
+	}
+
+	// sorting the files, for a stable output
+	files := []string{}
+	for file := range comparison {
+		files = append(files, file)
+	}
+
+	sort.Strings(files)
+
+	for _, file := range files {
+		if fileNode, isMap := asMap(comparison[file]); isMap {
+			addEntries(file, fileNode)
+		}
+	}
+
+	return baseline
+}
+
+//------------------------------------------------------------------------------
+// Subtracting the accepted differences
+//------------------------------------------------------------------------------
+
+// subtract returns the given comparison, without the differences accepted by this baseline for the given file
+func (thisBaseline *Baseline) subtract(file string, comparison Comparison) Comparison {
+	if thisBaseline == nil {
+		return comparison
+	}
+
+	if result, kept := thisBaseline.doSubtract(file, comparison, ""); kept {
+		return result
+	}
+
+	return nodif()
+}
+
+// doSubtract recursively rebuilds the given node without the accepted differences; returns false if nothing's left
+func (thisBaseline *Baseline) doSubtract(file string, node map[string]interface{}, currentPath string) (Comparison, bool) {
+	// a difference is either accepted, or kept
+	if kind, isDiff := getDiffKind(node); isDiff {
+		if thisBaseline.accepts(file, currentPath, kind, node) {
+			return nil, false
+		}
+
+		return node, true
+	}
+
+	result := Comparison{}
+
+	for key, child := range node {
+		childMap, isMap := asMap(child)
+		if !isMap {
+			result[key] = child
+
+			continue
+		}
+
+		// a move is a difference too
+		if key == markerMOVED {
+			if !thisBaseline.accepts(file, currentPath+">"+key, diffMOVED, childMap) {
+				result[key] = child
+			}
+
+			continue
+		}
+
+		if childResult, kept := thisBaseline.doSubtract(file, childMap, currentPath+">"+key); kept {
+			result[key] = childResult
+		}
+	}
+
+	return result, len(result) > 0
+}
+
+// accepts tells if the given difference is accepted by this baseline; if so, the corresponding entry is marked as used
+func (thisBaseline *Baseline) accepts(file, diffPath, kind string, diff map[string]interface{}) bool {
+	for _, entry := range thisBaseline.Entries {
+		if entry.matches(file, diffPath, kind, diff) {
+			thisBaseline.mx.Lock()
+			entry.used = true
+			thisBaseline.mx.Unlock()
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// matches tells if this entry corresponds to the given difference
+func (thisEntry *BaselineEntry) matches(file, diffPath, kind string, diff map[string]interface{}) bool {
+	if thisEntry.Path != diffPath || (thisEntry.Kind != "" && thisEntry.Kind != kind) {
+		return false
+	}
+
+	if thisEntry.File != file {
+		if matched, _ := path.Match(thisEntry.File, file); !matched {
+			return false
+		}
+	}
+
+	// the values in the 2 files
+	value1, value2 := diff[markerONE], diff[markerTWO]
+
+	switch kind {
+	case diffREMOVED:
+		value1 = diff[markerDEL]
+	case diffADDED:
+		value2 = diff[markerNEW]
+	case diffMOVED:
+		value1, value2 = diff[moveFROM], diff[moveTO]
+	}
+
+	if thisEntry.One != nil && !sameJsonValues(thisEntry.One, value1) {
+		return false
+	}
+
+	if thisEntry.Two != nil && !sameJsonValues(thisEntry.Two, value2) {
+		return false
+	}
+
+	if thisEntry.patternRegexp != nil {
+		for _, value := range []interface{}{value1, value2} {
+			if value != nil && !isNullMarker(value) && !thisEntry.patternRegexp.MatchString(fmt.Sprintf("%v", value)) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// getUnused returns the entries of this baseline that have not matched any difference
+func (thisBaseline *Baseline) getUnused() []*BaselineEntry {
+	unused := []*BaselineEntry{}
+
+	for _, entry := range thisBaseline.Entries {
+		if !entry.used {
+			unused = append(unused, entry)
+		}
+	}
+
+	return unused
+}
+
+// reportBaseline adds to the given comparison the baseline entries that no longer occur, if any
+func (thisComp *ComparisonOptions) reportBaseline(comparison Comparison) Comparison {
+	if thisComp.Baseline == nil {
+		return comparison
+	}
+
+	if unused := thisComp.Baseline.getUnused(); len(unused) > 0 {
+		if !thisComp.Silent {
+			thisComp.Logger.Warn("%d baseline entries did not match any difference", len(unused))
+		}
+
+		comparison[baselineUNUSED] = unused
+	}
+
+	return comparison
+}
+
+// sameJsonValues tells if 2 values are the same, once written as JSON - since a value read from a baseline file may have a different Go type
+func sameJsonValues(value1, value2 interface{}) bool {
+	// the numbers may have been decoded exactly, or not
+	if number1, isNumber1 := getExactNumber(value1); isNumber1 {
+		if number2, isNumber2 := getExactNumber(value2); isNumber2 {
+			return number1.Cmp(number2) == 0
+		}
+	}
+
+	json1, errMarsh1 := json.Marshal(value1)
+	json2, errMarsh2 := json.Marshal(value2)
+
+	if errMarsh1 != nil || errMarsh2 != nil {
+		return reflect.DeepEqual(value1, value2)
+	}
+
+	return string(json1) == string(json2)
+}
+
+// WriteBaseline writes out, as a JSON file, a baseline accepting all the differences of the given comparison, as well as the differences
+// accepted by the current baseline, if any, that are still occurring
+func (thisComp *ComparisonOptions) WriteBaseline(comparison Comparison, folders bool, filepath string) error {
+	baseline := BuildBaseline(comparison, folders)
+
+	if thisComp.Baseline != nil {
+		for _, entry := range thisComp.Baseline.Entries {
+			if entry.used {
+				baseline.Entries = append(baseline.Entries, entry)
+			}
+		}
+	}
+
+	// not escaping the '>' characters of the paths, so that the file remains readable
+	var baselineBytes bytes.Buffer
+
+	encoder := json.NewEncoder(&baselineBytes)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "	")
+
+	if errMarsh := encoder.Encode(baseline); errMarsh != nil {
+		return fmt.Errorf("Error while JSON-marshaling the baseline. Cause: %s", errMarsh)
+	}
+
+	//nolint:gosec,gomnd
+	if errWrite := os.WriteFile(filepath, baselineBytes.Bytes(), 0o644); errWrite != nil {
+		return fmt.Errorf("Error while writing the baseline file '%s'. Cause: %s", filepath, errWrite)
+	}
+
+	if !thisComp.Silent {
+		thisComp.Logger.Info("Wrote %d baseline entries into file '%s'", len(baseline.Entries), filepath)
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package core
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// the differences between these 2 payloads are of every kind, but moved
+const (
+	baselineDataOne = `{"price":"12.50","stock":null,"label":"old","tags":["a"]}`
+	baselineDataTwo = `{"price":12.5,"label":"new","tags":["a","b"],"active":true}`
+)
+
+func newBaselineTestOptions() *ComparisonOptions {
+	return newTestOptions("{}", func(options *ComparisonOptions) {
+		options.Lenient = true
+		options.NullPolicy = nullsSTRICT
+	})
+}
+
+func TestBaselineRoundTrip(t *testing.T) {
+	options := newBaselineTestOptions()
+	comparison := mustCompare(t, baselineDataOne, baselineDataTwo, options)
+
+	// writing out the differences as a baseline, and reading it back
+	baselineFile := filepath.Join(t.TempDir(), "baseline.json")
+
+	if errWrite := options.WriteBaseline(comparison, false, baselineFile); errWrite != nil {
+		t.Fatalf("cannot write the baseline: %s", errWrite)
+	}
+
+	baseline, errRead := ReadBaseline(baselineFile)
+	if errRead != nil {
+		t.Fatalf("cannot read the baseline: %s", errRead)
+	}
+
+	kinds := map[string]string{}
+	for _, entry := range baseline.Entries {
+		kinds[entry.Path] = entry.Kind
+	}
+
+	expectedKinds := map[string]string{
+		">price": diffCOERCED, ">stock": diffNULL, ">label": diffMODIFIED, ">tags>b": diffADDED, ">active": diffADDED,
+	}
+
+	for diffPath, kind := range expectedKinds {
+		if kinds[diffPath] != kind {
+			t.Errorf("expected kind '%s' at path '%s', got '%s'", kind, diffPath, kinds[diffPath])
+		}
+	}
+
+	// the baseline read back accepts all the differences it was built from
+	if remaining := baseline.subtract("", comparison); remaining.hasDiffs() {
+		t.Errorf("expected no remaining difference, got %s", toJson(t, remaining))
+	}
+
+	if unused := baseline.getUnused(); len(unused) > 0 {
+		t.Errorf("expected all the entries to be used, got %d unused ones", len(unused))
+	}
+}
+
+func TestBaselineSubtract(t *testing.T) {
+	options := newBaselineTestOptions()
+
+	// reading the baseline back from JSON, as its values then have different Go types
+	baselineFile := filepath.Join(t.TempDir(), "baseline.json")
+
+	if errWrite := options.WriteBaseline(mustCompare(t, baselineDataOne, baselineDataTwo, options), false, baselineFile); errWrite != nil {
+		t.Fatalf("cannot write the baseline: %s", errWrite)
+	}
+
+	baseline, errRead := ReadBaseline(baselineFile)
+	if errRead != nil {
+		t.Fatalf("cannot read the baseline: %s", errRead)
+	}
+
+	// the label has changed differently, the stock is now null on both sides, and the tags are the same
+	comparison := mustCompare(t, baselineDataOne, `{"price":12.5,"label":"newer","tags":["a"],"active":true,"stock":null}`, options)
+
+	remaining := baseline.subtract("", comparison)
+	if expected, actual := `{"label":{"_one_":"old","_two_":"newer"}}`, toJson(t, remaining); actual != expected {
+		t.Errorf("expected %s, got %s", expected, actual)
+	}
+
+	// the entries for the stock and the tags no longer match anything - and neither does the one for the label
+	unused := map[string]bool{}
+	for _, entry := range baseline.getUnused() {
+		unused[entry.Path] = true
+	}
+
+	if len(unused) != 3 || !unused[">stock"] || !unused[">tags>b"] || !unused[">label"] {
+		t.Errorf("unexpected unused entries: %v", unused)
+	}
+}
+
+func TestBaselineEntryMatches(t *testing.T) {
+	diff := map[string]interface{}{markerONE: "old", markerTWO: "new"}
+
+	cases := []struct {
+		name     string
+		entry    *BaselineEntry
+		file     string
+		expected bool
+	}{
+		{"any value", &BaselineEntry{Path: ">label"}, "", true},
+		{"other path", &BaselineEntry{Path: ">name"}, "", false},
+		{"same kind", &BaselineEntry{Path: ">label", Kind: diffMODIFIED}, "", true},
+		{"other kind", &BaselineEntry{Path: ">label", Kind: diffADDED}, "", false},
+		{"same values", &BaselineEntry{Path: ">label", One: "old", Two: "new"}, "", true},
+		{"other value", &BaselineEntry{Path: ">label", Two: "newer"}, "", false},
+		{"matching pattern", &BaselineEntry{Path: ">label", Pattern: "^(old|new)$"}, "", true},
+		{"unmatching pattern", &BaselineEntry{Path: ">label", Pattern: "^old$"}, "", false},
+		{"file pattern", &BaselineEntry{File: "orders/*.json", Path: ">label"}, "orders/1.json", true},
+		{"other file", &BaselineEntry{File: "orders/*.json", Path: ">label"}, "customers/1.json", false},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			baseline := &Baseline{Entries: []*BaselineEntry{testCase.entry}}
+			if errResolve := baseline.Resolve(); errResolve != nil {
+				t.Fatalf("unexpected error: %s", errResolve)
+			}
+
+			if actual := testCase.entry.matches(testCase.file, ">label", diffMODIFIED, diff); actual != testCase.expected {
+				t.Errorf("expected %v, got %v", testCase.expected, actual)
+			}
+		})
+	}
+}
@@ -2,10 +2,7 @@ package core
 
 import (
 	"bytes"
-	"encoding/json"
 	"fmt"
-
-	"github.com/sbabiv/xml2map"
 )
 
 //------------------------------------------------------------------------------
@@ -14,60 +11,79 @@ import (
 
 // compareBytes : comparing 2 slices of bytes containing the data for JSON or XML files
 func compareBytes(bytes1, bytes2 []byte, options *ComparisonOptions, doLog bool) (Comparison, error) {
-	// if the XML option is activated, we compare 2 XML files
-	if options.GetFileType() == FileTypeXML {
-		// handling the XML unmarshalling
-		if doLog {
-			options.Logger.Info("Unmarshalling the first file")
-		}
-
-		map1, err1 := xml2map.NewDecoder(bytes.NewReader(bytes1)).Decode()
-		if err1 != nil {
-			return nil, fmt.Errorf("Error while unmarshalling the first XML data set. Cause: %s", err1)
-		}
-
-		if doLog {
-			options.Logger.Info("Unmarshalling the second file")
-		}
-
-		map2, err2 := xml2map.NewDecoder(bytes.NewReader(bytes2)).Decode()
-		if err2 != nil {
-			return nil, fmt.Errorf("Error while unmarshalling the second XML data set. Cause: %s", err2)
-		}
-
-		if doLog {
-			options.Logger.Info("Done unmarshalling the two files")
-		}
-
-		// using the right comparison function, between 2 objects in general
-		return compareJsonEntities(options.IdParams, entity(map1), entity(map2), options, "", false)
-	}
-
-	// handling the JSON unmarshalling
-	var obj1 interface{}
+	// each file can be a JSON or an XML file
+	fileType1, fileType2 := options.sniffFileType(bytes1), options.sniffFileType(bytes2)
 
+	// handling the unmarshalling
 	if doLog {
 		options.Logger.Info("Unmarshalling the first file")
 	}
 
-	if errUnmarsh1 := json.Unmarshal(bytes1, &obj1); errUnmarsh1 != nil {
-		return nil, fmt.Errorf("Error while unmarshalling the first JSON data set. Cause: %s", errUnmarsh1)
+	obj1, err1 := unmarshalBytes(bytes1, fileType1, options)
+	if err1 != nil {
+		return nil, fmt.Errorf("Error while unmarshalling the first %s data set. Cause: %s", fileType1, err1)
 	}
 
-	var obj2 interface{}
-
 	if doLog {
 		options.Logger.Info("Unmarshalling the second file")
 	}
 
-	if errUnmarsh2 := json.Unmarshal(bytes2, &obj2); errUnmarsh2 != nil {
-		return nil, fmt.Errorf("Error while unmarshalling the second JSON data set. Cause: %s", errUnmarsh2)
+	obj2, err2 := unmarshalBytes(bytes2, fileType2, options)
+	if err2 != nil {
+		return nil, fmt.Errorf("Error while unmarshalling the second %s data set. Cause: %s", fileType2, err2)
 	}
 
 	if doLog {
 		options.Logger.Info("Done unmarshalling the two files")
 	}
 
+	// if we compare 2 XML files, we compare maps
+	if fileType1 == FileTypeXML && fileType2 == FileTypeXML {
+		// using the right comparison function, between 2 objects in general
+		return compareJsonEntities(options.IdParams, entity(obj1.(map[string]interface{})), entity(obj2.(map[string]interface{})), options, "", false)
+	}
+
+	idParams := options.IdParams
+
+	// comparing an XML file with a JSON file: the XML data is mapped to its JSON equivalent, and the values are compared leniently,
+	// since XML only has strings
+	if fileType1 != fileType2 {
+		if doLog {
+			options.Logger.Info("Comparing a %s file with a %s file", fileType1, fileType2)
+		}
+
+		if fileType1 == FileTypeXML {
+			obj1, idParams = options.mapXmlDocument(obj1.(map[string]interface{}), obj2, idParams)
+		} else {
+			obj2, idParams = options.mapXmlDocument(obj2.(map[string]interface{}), obj1, idParams)
+		}
+
+		lenientOptions := *options
+		lenientOptions.Lenient, lenientOptions.implicitLenient = true, !options.Lenient
+		options = &lenientOptions
+	}
+
 	// using the right comparison function, between 2 objects in general
-	return compareObjects(nil, nil, options.IdParams, obj1, obj2, options, "")
+	return compareObjects(nil, nil, idParams, obj1, obj2, options, "")
+}
+
+// unmarshalBytes : unmarshalling the data of a JSON or XML file; in the XML case, the result is always a map
+func unmarshalBytes(data []byte, fileType FileType, options *ComparisonOptions) (interface{}, error) {
+	// handling the XML unmarshalling
+	if fileType == FileTypeXML {
+		obj, errDecode := decodeXml(bytes.NewReader(data), options)
+		if errDecode != nil {
+			return nil, errDecode
+		}
+
+		// the repeatable elements are always arrays
+		if options.Repeatable != nil {
+			options.Repeatable.apply(obj, "", options.getXmlAttrPrefix())
+		}
+
+		return obj, nil
+	}
+
+	// handling the JSON unmarshalling
+	return unmarshalJson(data, options.UseNumber)
 }
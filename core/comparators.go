@@ -0,0 +1,97 @@
+package core
+
+import (
+	"regexp"
+	"strings"
+)
+
+//------------------------------------------------------------------------------
+// Here we allow the library users to register their own ways of comparing the
+// values at given paths, e.g. timestamps in different time zones, or URLs
+// whatever the order of their query parameters
+//------------------------------------------------------------------------------
+
+// the marker used to give the message of a custom comparator, along with a difference it found
+const markerMSG = "_msg_"
+
+// Comparator : a custom way of comparing 2 non-nil values; returns true if they're to be considered equal, else false, with an optional message
+// to output along with the difference
+type Comparator func(value1, value2 interface{}) (equal bool, message string)
+
+// comparatorPattern : a comparator to use at the paths matching a regular expression
+type comparatorPattern struct {
+	pattern    *regexp.Regexp
+	comparator Comparator
+}
+
+// RegisterComparator registers a comparator to use for the values at the given path, as written in the ID params, e.g. "data.vehicule.date";
+// the path does not need to be declared in the ID params, as long as its parent is
+func (thisComp *ComparisonOptions) RegisterComparator(path string, comparator Comparator) {
+	if thisComp.comparators == nil {
+		thisComp.comparators = map[string]Comparator{}
+	}
+
+	thisComp.comparators[strings.TrimPrefix(path, ".")] = comparator
+}
+
+// RegisterComparatorMatching registers a comparator to use for the values whose full path matches the given regular expression - a full
+// path containing the keys of the array elements, e.g. ">data>vehicule>ABC123>date"; the comparators registered with an exact path prevail
+func (thisComp *ComparisonOptions) RegisterComparatorMatching(pattern *regexp.Regexp, comparator Comparator) {
+	thisComp.comparatorPatterns = append(thisComp.comparatorPatterns, &comparatorPattern{pattern: pattern, comparator: comparator})
+}
+
+// getComparator returns the comparator registered for the given ID param path, or matching the given full path, if any - the built-in
+// comparators not included
+func (thisComp *ComparisonOptions) getComparator(paramPath, currentPathValue string) Comparator {
+	if paramPath != "" {
+		if comparator := thisComp.comparators[strings.TrimPrefix(paramPath, ".")]; comparator != nil {
+			return comparator
+		}
+	}
+
+	for _, registered := range thisComp.comparatorPatterns {
+		if registered.pattern.MatchString(currentPathValue) {
+			return registered.comparator
+		}
+	}
+
+	return nil
+}
+
+// compareCustom compares 2 non-nil values with the comparator registered for their path, if any; returns false if there's none
+func (thisComp *ComparisonOptions) compareCustom(idParam *IdentificationParameter, obj1, obj2 interface{}, currentPathValue string) (Comparison, bool) {
+	if obj1 == nil || obj2 == nil || (thisComp.comparators == nil && thisComp.comparatorPatterns == nil) {
+		return nil, false
+	}
+
+	comparator := thisComp.getComparator(idParam.toString(), currentPathValue)
+	if comparator == nil {
+		return nil, false
+	}
+
+	return applyComparator(comparator, obj1, obj2), true
+}
+
+// applyComparator uses the given comparator on 2 values, and builds the resulting comparison
+func applyComparator(comparator Comparator, obj1, obj2 interface{}) Comparison {
+	equal, message := comparator(obj1, obj2)
+	if equal {
+		return nodif()
+	}
+
+	comparison := one_two(obj1, obj2)
+	if message != "" {
+		comparison[markerMSG] = message
+	}
+
+	return comparison
+}
+
+// getPropertyParamPath returns the ID param path of an object's property which is not declared in the ID params, from its parent's ID param
+func getPropertyParamPath(parentParam *IdentificationParameter, currentPathValue string) string {
+	if parentParam == nil {
+		return ""
+	}
+
+	return parentParam.toString() + "." + currentPathValue[strings.LastIndex(currentPathValue, ">")+1:]
+}
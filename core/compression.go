@@ -0,0 +1,204 @@
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+)
+
+//------------------------------------------------------------------------------
+// Here we handle compressed files (gzip, zstd, bzip2), which are transparently
+// decompressed before being compared
+//------------------------------------------------------------------------------
+
+// the compression formats we can handle
+type compressionType string
+
+const (
+	compressionNONE  compressionType = ""
+	compressionGZIP  compressionType = "gzip"
+	compressionZSTD  compressionType = "zstd"
+	compressionBZIP2 compressionType = "bzip2"
+)
+
+// the magic bytes found at the beginning of compressed files
+var compressionMagics = map[compressionType][]byte{
+	compressionGZIP:  {0x1f, 0x8b},
+	compressionZSTD:  {0x28, 0xb5, 0x2f, 0xfd},
+	compressionBZIP2: {'B', 'Z', 'h'},
+}
+
+// the extensions of compressed files
+var compressionExtensions = map[string]compressionType{
+	".gz":   compressionGZIP,
+	".gzip": compressionGZIP,
+	".zst":  compressionZSTD,
+	".zstd": compressionZSTD,
+	".bz2":  compressionBZIP2,
+}
+
+// getCompressionFromExtension returns the compression type associated with the given file name's extension, and the name without this extension
+func getCompressionFromExtension(filename string) (compressionType, string) {
+	lowerName := strings.ToLower(filename)
+
+	for extension, compression := range compressionExtensions {
+		if strings.HasSuffix(lowerName, extension) {
+			return compression, filename[:len(filename)-len(extension)]
+		}
+	}
+
+	return compressionNONE, filename
+}
+
+// uncompressedName returns the name the given file has once decompressed, e.g. "foo.json" for "foo.json.gz"
+func uncompressedName(filename string) string {
+	_, name := getCompressionFromExtension(filename)
+
+	return name
+}
+
+// getCompression determines the compression used for a file, from its first bytes, or else from its extension
+func getCompression(filename string, firstBytes []byte) compressionType {
+	for compression, magic := range compressionMagics {
+		if bytes.HasPrefix(firstBytes, magic) {
+			return compression
+		}
+	}
+
+	compression, _ := getCompressionFromExtension(filename)
+
+	return compression
+}
+
+//------------------------------------------------------------------------------
+// Decompressing
+//------------------------------------------------------------------------------
+
+// decompressReader returns a reader streaming the decompressed content of the given reader, if it's compressed;
+// else, the returned reader streams the original content; in any case, the returned reader must be closed
+func decompressReader(filename string, reader io.Reader) (io.ReadCloser, error) {
+	bufReader := bufio.NewReader(reader)
+
+	// we just need a peek at the first bytes here; a short file is not an error
+	//nolint:gomnd
+	firstBytes, _ := bufReader.Peek(4)
+
+	var decompressed io.ReadCloser
+
+	var errDecomp error
+
+	switch compression := getCompression(filename, firstBytes); compression {
+	case compressionNONE:
+		return io.NopCloser(bufReader), nil
+	case compressionGZIP:
+		decompressed, errDecomp = gzip.NewReader(bufReader)
+	case compressionBZIP2:
+		decompressed = io.NopCloser(bzip2.NewReader(bufReader))
+	case compressionZSTD:
+		decompressed, errDecomp = newCommandReader(bufReader, "zstd", "-d", "-c", "-q")
+	default:
+		errDecomp = fmt.Errorf("unhandled compression: %s", compression)
+	}
+
+	if errDecomp != nil {
+		return nil, fmt.Errorf("Error while decompressing file '%s'. Cause: %s", filename, errDecomp)
+	}
+
+	return decompressed, nil
+}
+
+// decompressBytes returns the decompressed version of the given bytes, if they're compressed
+func decompressBytes(filename string, fileBytes []byte) ([]byte, error) {
+	if getCompression(filename, fileBytes) == compressionNONE {
+		return fileBytes, nil
+	}
+
+	reader, errDecomp := decompressReader(filename, bytes.NewReader(fileBytes))
+	if errDecomp != nil {
+		return nil, errDecomp
+	}
+
+	defer reader.Close()
+
+	decompressedBytes, errRead := io.ReadAll(reader)
+	if errRead != nil {
+		return nil, fmt.Errorf("Error while decompressing file '%s'. Cause: %s", filename, errRead)
+	}
+
+	return decompressedBytes, nil
+}
+
+//------------------------------------------------------------------------------
+// Some formats (zstd) are not handled by Go's standard library, so we're
+// streaming the data through the corresponding command, which must be installed
+//------------------------------------------------------------------------------
+
+type commandReader struct {
+	cmd    *exec.Cmd
+	stdout io.Reader
+	stderr *bytes.Buffer
+	done   bool // true once the command has been waited for
+}
+
+func newCommandReader(input io.Reader, command string, args ...string) (*commandReader, error) {
+	if _, errLook := exec.LookPath(command); errLook != nil {
+		return nil, fmt.Errorf("the '%s' command is required to decompress this file, but cannot be found (%s)", command, errLook)
+	}
+
+	cmd := exec.Command(command, args...)
+	cmd.Stdin = input
+
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+
+	stdout, errPipe := cmd.StdoutPipe()
+	if errPipe != nil {
+		return nil, errPipe
+	}
+
+	if errStart := cmd.Start(); errStart != nil {
+		return nil, fmt.Errorf("cannot run the '%s' command (%s)", command, errStart)
+	}
+
+	return &commandReader{cmd: cmd, stdout: stdout, stderr: stderr}, nil
+}
+
+func (thisReader *commandReader) Read(buffer []byte) (int, error) {
+	nbRead, errRead := thisReader.stdout.Read(buffer)
+
+	switch {
+	case errRead == io.EOF:
+		// we've reached the end of the output; let's make sure the command succeeded
+		thisReader.done = true
+
+		if errWait := thisReader.cmd.Wait(); errWait != nil {
+			return nbRead, fmt.Errorf("the '%s' command failed (%s): %s", thisReader.cmd.Path, errWait, strings.TrimSpace(thisReader.stderr.String()))
+		}
+
+	case errRead != nil:
+		// the output cannot be read any further, so the command has to be stopped
+		thisReader.Close()
+	}
+
+	return nbRead, errRead
+}
+
+// Close stops the command, if it's still running, so that it's always reaped - even when the output is not read until the end
+func (thisReader *commandReader) Close() error {
+	if thisReader.done {
+		return nil
+	}
+
+	thisReader.done = true
+
+	// the command may have exited on its own already
+	_ = thisReader.cmd.Process.Kill()
+	_ = thisReader.cmd.Wait()
+
+	return nil
+}
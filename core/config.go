@@ -0,0 +1,249 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+//------------------------------------------------------------------------------
+// Here we read configuration files - like the ID params - which can be written
+// in JSON, JSON with comments, or YAML, and split into several files
+//------------------------------------------------------------------------------
+
+// the key of a node to be replaced by the content of another configuration file
+const configINCLUDE = "$include"
+
+// the possible syntaxes of a configuration
+type configSyntax string
+
+const (
+	configJSONC configSyntax = "JSONC" // JSON, with comments and trailing commas allowed
+	configYAML  configSyntax = "YAML"
+)
+
+// getConfigJson returns the JSON bytes for a configuration given either as a string, or as the path to a file, in JSON, JSONC or YAML;
+// the "$include" nodes are replaced by the content of the designated files
+func getConfigJson(str string) ([]byte, error) {
+	// at first, we suppose the whole string has been provided, but what if it's the path to an existing file ?
+	if _, errExist := os.Stat(str); errExist == nil {
+		config, errRead := readConfigFile(str, nil)
+		if errRead != nil {
+			return nil, errRead
+		}
+
+		return json.Marshal(config)
+	}
+
+	config, errParse := parseConfig([]byte(str), getConfigSyntax("", []byte(str)))
+	if errParse != nil {
+		return nil, errParse
+	}
+
+	if config, errParse = resolveIncludes(config, ".", nil); errParse != nil {
+		return nil, errParse
+	}
+
+	return json.Marshal(config)
+}
+
+// readConfigFile reads and parses a configuration file, resolving its includes; `including` are the files currently being read, to detect cycles
+func readConfigFile(path string, including []string) (interface{}, error) {
+	absPath, errAbs := filepath.Abs(path)
+	if errAbs != nil {
+		return nil, fmt.Errorf("Error while locating the config file '%s'. Cause: %s", path, errAbs)
+	}
+
+	for _, included := range including {
+		if included == absPath {
+			return nil, fmt.Errorf("Cyclic inclusion of the config file '%s'", path)
+		}
+	}
+
+	fileBytes, errRead := os.ReadFile(path)
+	if errRead != nil {
+		return nil, fmt.Errorf("Error while reading the config file '%s'. Cause: %s", path, errRead)
+	}
+
+	config, errParse := parseConfig(fileBytes, getConfigSyntax(path, fileBytes))
+	if errParse != nil {
+		return nil, fmt.Errorf("Invalid config file '%s'. Cause: %s", path, errParse)
+	}
+
+	return resolveIncludes(config, filepath.Dir(path), append(including, absPath))
+}
+
+// getConfigSyntax determines the syntax of a configuration, by the extension of its file if any, or else by its content
+func getConfigSyntax(path string, content []byte) configSyntax {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return configYAML
+	case ".json", ".jsonc":
+		return configJSONC
+	}
+
+	// a JSON configuration is always an object
+	if trimmed := strings.TrimSpace(string(stripJsonComments(content))); strings.HasPrefix(trimmed, "{") {
+		return configJSONC
+	}
+
+	return configYAML
+}
+
+// parseConfig parses a configuration written with the given syntax
+func parseConfig(content []byte, syntax configSyntax) (interface{}, error) {
+	var config interface{}
+
+	if syntax == configYAML {
+		if errUnmarsh := yaml.Unmarshal(content, &config); errUnmarsh != nil {
+			return nil, fmt.Errorf("not a valid YAML: %s", errUnmarsh)
+		}
+
+		return config, nil
+	}
+
+	// the comments are blanked out, so the error positions remain right
+	stripped := stripJsonComments(content)
+
+	if errUnmarsh := json.Unmarshal(stripped, &config); errUnmarsh != nil {
+		return nil, fmt.Errorf("%s", describeJsonError(stripped, errUnmarsh))
+	}
+
+	return config, nil
+}
+
+// resolveIncludes recursively replaces the "$include" nodes with the content of the designated files, whose paths are relative to `dir`;
+// the other properties of an including node override the included ones
+func resolveIncludes(node interface{}, dir string, including []string) (interface{}, error) {
+	switch typedNode := node.(type) {
+	case map[string]interface{}:
+		result := map[string]interface{}{}
+
+		if includePath, hasInclude := typedNode[configINCLUDE]; hasInclude {
+			includePathString, isString := includePath.(string)
+			if !isString {
+				return nil, fmt.Errorf("the value of '%s' should be a file path, not: %v", configINCLUDE, includePath)
+			}
+
+			if !filepath.IsAbs(includePathString) {
+				includePathString = filepath.Join(dir, includePathString)
+			}
+
+			included, errInclude := readConfigFile(includePathString, including)
+			if errInclude != nil {
+				return nil, errInclude
+			}
+
+			includedMap, isMap := included.(map[string]interface{})
+			if !isMap {
+				// nothing to merge with
+				if len(typedNode) == 1 {
+					return included, nil
+				}
+
+				return nil, fmt.Errorf("the included config file '%s' should contain an object", includePathString)
+			}
+
+			for key, value := range includedMap {
+				result[key] = value
+			}
+		}
+
+		for key, value := range typedNode {
+			if key == configINCLUDE {
+				continue
+			}
+
+			resolved, errResolve := resolveIncludes(value, dir, including)
+			if errResolve != nil {
+				return nil, errResolve
+			}
+
+			result[key] = resolved
+		}
+
+		return result, nil
+
+	case []interface{}:
+		result := make([]interface{}, len(typedNode))
+
+		for index, value := range typedNode {
+			resolved, errResolve := resolveIncludes(value, dir, including)
+			if errResolve != nil {
+				return nil, errResolve
+			}
+
+			result[index] = resolved
+		}
+
+		return result, nil
+	}
+
+	return node, nil
+}
+
+// stripJsonComments blanks out the comments (// and /* */) and the trailing commas of a JSONC content, keeping the line breaks
+func stripJsonComments(content []byte) []byte {
+	result := make([]byte, len(content))
+	copy(result, content)
+
+	inString, lastComma := false, -1
+
+	for i := 0; i < len(result); i++ {
+		char := result[i]
+
+		if inString {
+			if char == '\\' {
+				i++
+			} else if char == '"' {
+				inString = false
+			}
+
+			continue
+		}
+
+		switch {
+		case char == '"':
+			inString, lastComma = true, -1
+
+		case char == '/' && i+1 < len(result) && result[i+1] == '/':
+			for ; i < len(result) && result[i] != '\n'; i++ {
+				result[i] = ' '
+			}
+
+		case char == '/' && i+1 < len(result) && result[i+1] == '*':
+			result[i], result[i+1] = ' ', ' '
+
+			for i += 2; i < len(result) && !(result[i] == '*' && i+1 < len(result) && result[i+1] == '/'); i++ {
+				if result[i] != '\n' {
+					result[i] = ' '
+				}
+			}
+
+			if i+1 < len(result) {
+				result[i], result[i+1] = ' ', ' '
+				i++
+			}
+
+		case char == ',':
+			lastComma = i
+
+		case char == '}' || char == ']':
+			// a comma right before a closing bracket is a trailing comma
+			if lastComma >= 0 {
+				result[lastComma] = ' '
+			}
+
+			lastComma = -1
+
+		case char != ' ' && char != '\t' && char != '\n' && char != '\r':
+			lastComma = -1
+		}
+	}
+
+	return result
+}
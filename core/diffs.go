@@ -0,0 +1,87 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+//------------------------------------------------------------------------------
+// Here we compare 2 comparison results, e.g. yesterday's and today's, to know
+// which differences have been fixed, which are new, and which have changed
+//------------------------------------------------------------------------------
+
+// the categories of differences between 2 comparison results
+const (
+	diffsFIXED   = "fixed"   // the differences only found in the first result
+	diffsNEW     = "new"     // the differences only found in the second result
+	diffsCHANGED = "changed" // the differences found in both results, but with different values
+)
+
+// CompareComparisons : getting the differences between 2 comparison results, written out as JSON files; the differences, identified by
+// their path, are reported as fixed, new or changed
+func CompareComparisons(filepathOne, filepathTwo string, options *ComparisonOptions) (Comparison, error) {
+	diffsOne, errOne := readComparisonDiffs(filepathOne)
+	if errOne != nil {
+		return nil, errOne
+	}
+
+	diffsTwo, errTwo := readComparisonDiffs(filepathTwo)
+	if errTwo != nil {
+		return nil, errTwo
+	}
+
+	fixed, added, changed := Comparison{}, Comparison{}, Comparison{}
+
+	for diffPath, diffOne := range diffsOne {
+		diffTwo, inTwo := diffsTwo[diffPath]
+
+		switch {
+		case !inTwo:
+			fixed[diffPath] = diffOne
+		case !sameJsonValues(diffOne, diffTwo):
+			changed[diffPath] = one_two(diffOne, diffTwo)
+		}
+	}
+
+	for diffPath, diffTwo := range diffsTwo {
+		if _, inOne := diffsOne[diffPath]; !inOne {
+			added[diffPath] = diffTwo
+		}
+	}
+
+	if !options.Silent {
+		options.Logger.Info("Differences: %d fixed, %d new, %d changed", len(fixed), len(added), len(changed))
+	}
+
+	result := Comparison{}
+
+	for category, diffs := range map[string]Comparison{diffsFIXED: fixed, diffsNEW: added, diffsCHANGED: changed} {
+		if diffs.hasDiffs() {
+			result[category] = diffs
+		}
+	}
+
+	return result, nil
+}
+
+// readComparisonDiffs reads a comparison result, and returns its differences by path
+func readComparisonDiffs(filepath string) (map[string]map[string]interface{}, error) {
+	fileBytes, errRead := readFile(filepath)
+	if errRead != nil {
+		return nil, fmt.Errorf("Error while reading the comparison file '%s'. Cause: %s", filepath, errRead)
+	}
+
+	comparison := map[string]interface{}{}
+
+	if errUnmarsh := json.Unmarshal(fileBytes, &comparison); errUnmarsh != nil {
+		return nil, fmt.Errorf("Error while unmarshalling the comparison file '%s'. Cause: %s", filepath, errUnmarsh)
+	}
+
+	diffs := map[string]map[string]interface{}{}
+
+	walkDiffs(comparison, "", func(diffPath, kind string, diff map[string]interface{}) {
+		diffs[diffPath] = diff
+	})
+
+	return diffs, nil
+}
@@ -0,0 +1,157 @@
+package core
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+)
+
+//------------------------------------------------------------------------------
+// Here we handle the JSON or XML payloads embedded in strings - possibly
+// encoded in base64 - so that they're decoded, and compared as data trees
+//------------------------------------------------------------------------------
+
+// the ways the strings can embed some data
+const (
+	embeddedJSON   = "json"   // the strings are JSON documents
+	embeddedXML    = "xml"    // the strings are XML documents
+	embeddedBASE64 = "base64" // the strings are encoded in base64; the decoded content can be JSON, XML, or just text
+	embeddedAUTO   = "auto"   // the strings may be JSON or XML documents, possibly encoded in base64
+)
+
+// getEmbedded returns how the strings at this ID param's path embed some data, if they do; the undeclared paths depend on the options
+func (thisParam *IdentificationParameter) getEmbedded(options *ComparisonOptions) string {
+	if thisParam != nil && thisParam.Embedded != "" {
+		return thisParam.Embedded
+	}
+
+	if options.Embedded {
+		return embeddedAUTO
+	}
+
+	return ""
+}
+
+// compareEmbedded compares 2 strings as the data trees they embed, if they do, with the given ID param as the root ID param of these data trees;
+// returns false if at least 1 of the strings does not embed any data
+func compareEmbedded(idParam *IdentificationParameter, obj1, obj2 interface{}, options *ComparisonOptions, currentPathValue string) (Comparison, bool, error) {
+	embedded := idParam.getEmbedded(options)
+	if embedded == "" {
+		return nil, false, nil
+	}
+
+	str1, isString1 := obj1.(string)
+	str2, isString2 := obj2.(string)
+
+	if !isString1 || !isString2 || str1 == str2 {
+		return nil, false, nil
+	}
+
+	decoded1, isDecoded1 := options.decodeEmbedded(str1, embedded)
+	decoded2, isDecoded2 := options.decodeEmbedded(str2, embedded)
+
+	if !isDecoded1 || !isDecoded2 {
+		// the payloads declared at this path should be decodable
+		if embedded != embeddedAUTO && !options.Silent {
+			options.Logger.Warn("Could not decode the %s payloads at path '%s'; comparing them as strings", embedded, currentPathValue)
+		}
+
+		return nil, false, nil
+	}
+
+	// the payloads may just be some text, not to decode again
+	text1, isText1 := decoded1.(string)
+	text2, isText2 := decoded2.(string)
+
+	if isText1 && isText2 {
+		if text1 != text2 {
+			return one_two(text1, text2), true, nil
+		}
+
+		return nodif(), true, nil
+	}
+
+	// the embedded data trees are compared on their own, hence no roots
+	comparison, errComp := compareObjects(nil, nil, idParam, decoded1, decoded2, options, currentPathValue)
+
+	return comparison, true, errComp
+}
+
+// decodeEmbedded decodes the data embedded in the given string, in the given way; returns false if the string does not hold such data
+func (thisComp *ComparisonOptions) decodeEmbedded(str string, embedded string) (interface{}, bool) {
+	data := bytes.TrimSpace([]byte(str))
+	if len(data) == 0 {
+		return nil, false
+	}
+
+	switch embedded {
+	case embeddedJSON:
+		return thisComp.decodeEmbeddedJson(data)
+
+	case embeddedXML:
+		return thisComp.decodeEmbeddedXml(data)
+
+	case embeddedBASE64:
+		decodedData, isBase64 := decodeBase64(data)
+		if !isBase64 {
+			return nil, false
+		}
+
+		// the decoded content may just be some text
+		if decoded, isDecoded := thisComp.decodeEmbedded(string(decodedData), embeddedAUTO); isDecoded {
+			return decoded, true
+		}
+
+		return string(decodedData), true
+
+	case embeddedAUTO:
+		switch data[0] {
+		case '{', '[':
+			return thisComp.decodeEmbeddedJson(data)
+		case '<':
+			return thisComp.decodeEmbeddedXml(data)
+		}
+
+		// only the base64 payloads that hold JSON or XML are considered as such, since many simple strings are valid base64
+		if decodedData, isBase64 := decodeBase64(data); isBase64 {
+			if decodedData = bytes.TrimSpace(decodedData); len(decodedData) > 0 && strings.ContainsRune("{[<", rune(decodedData[0])) {
+				return thisComp.decodeEmbedded(string(decodedData), embeddedAUTO)
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// decodeEmbeddedJson decodes an embedded JSON document
+func (thisComp *ComparisonOptions) decodeEmbeddedJson(data []byte) (interface{}, bool) {
+	decoded, errDecode := unmarshalJson(data, thisComp.UseNumber)
+	if errDecode != nil {
+		return nil, false
+	}
+
+	return decoded, true
+}
+
+// decodeEmbeddedXml decodes an embedded XML document
+func (thisComp *ComparisonOptions) decodeEmbeddedXml(data []byte) (interface{}, bool) {
+	decoded, errDecode := decodeXml(bytes.NewReader(data), thisComp)
+	if errDecode != nil || len(decoded) == 0 {
+		return nil, false
+	}
+
+	return decoded, true
+}
+
+// decodeBase64 decodes some base64 data, padded or not, possibly URL-safe
+func decodeBase64(data []byte) ([]byte, bool) {
+	for _, encoding := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		decoded := make([]byte, encoding.DecodedLen(len(data)))
+
+		if nbBytes, errDecode := encoding.Decode(decoded, data); errDecode == nil {
+			return decoded[:nbBytes], true
+		}
+	}
+
+	return nil, false
+}
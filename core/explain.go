@@ -0,0 +1,121 @@
+package core
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+//------------------------------------------------------------------------------
+// Here we record how the keys of the array elements are built, to be able to
+// explain them - e.g. when 2 elements end up with the same key
+//------------------------------------------------------------------------------
+
+// the value of the Explain option to explain the keys built at all the paths
+const explainALL = "*"
+
+// keyTrace : a step in the building of a key, with its sub-steps
+type keyTrace struct {
+	label    string
+	children []*keyTrace
+	isFile   bool // true if this node groups the traces of a compared file, when comparing folders
+}
+
+// add records a sub-step of this step, and returns it; does nothing if there's no trace to record
+func (thisTrace *keyTrace) add(format string, args ...interface{}) *keyTrace {
+	if thisTrace == nil {
+		return nil
+	}
+
+	child := &keyTrace{label: fmt.Sprintf(format, args...)}
+	thisTrace.children = append(thisTrace.children, child)
+
+	return child
+}
+
+// write outputs this trace as a tree
+func (thisTrace *keyTrace) write(writer io.Writer, indent string, last bool, root bool) {
+	childIndent := indent
+
+	if root {
+		fmt.Fprintln(writer, thisTrace.label)
+	} else {
+		branch, nextIndent := "├─ ", "│  "
+		if last {
+			branch, nextIndent = "└─ ", "   "
+		}
+
+		fmt.Fprintln(writer, indent+branch+thisTrace.label)
+
+		childIndent = indent + nextIndent
+	}
+
+	for i, child := range thisTrace.children {
+		child.write(writer, childIndent, i == len(thisTrace.children)-1, false)
+	}
+}
+
+// newKeyTrace starts recording the building of the key of an array element, if it has to be explained
+func (thisComp *ComparisonOptions) newKeyTrace(idParam *IdentificationParameter, file int, currentPathValue string, index int) *keyTrace {
+	if thisComp.Explain == "" || (thisComp.Explain != explainALL && thisComp.Explain != strings.TrimPrefix(idParam.toString(), ".")) {
+		return nil
+	}
+
+	trace := &keyTrace{label: fmt.Sprintf("%s - file %d, at '%s', element #%d", strings.TrimPrefix(idParam.toString(), "."), file, currentPathValue, index)}
+
+	thisComp.explainMx.Lock()
+	thisComp.explanations.children = append(thisComp.explanations.children, trace)
+	thisComp.explainMx.Unlock()
+
+	return trace
+}
+
+// forExplainedFile returns a copy of these options, recording the traces of the keys built when comparing the given file in their own group,
+// if the keys have to be explained; when comparing folders, this tells which file a trace comes from
+func (thisComp *ComparisonOptions) forExplainedFile(file string) *ComparisonOptions {
+	if thisComp.Explain == "" || thisComp.explanations == nil {
+		return thisComp
+	}
+
+	fileOptions := *thisComp
+	fileOptions.explanations = &keyTrace{label: fmt.Sprintf("file '%s'", file), isFile: true}
+
+	thisComp.explainMx.Lock()
+	thisComp.explanations.children = append(thisComp.explanations.children, fileOptions.explanations)
+	thisComp.explainMx.Unlock()
+
+	return &fileOptions
+}
+
+// WriteExplanations outputs, as trees, how the keys have been built for the path given with the Explain option
+func (thisComp *ComparisonOptions) WriteExplanations(writer io.Writer) {
+	if thisComp.Explain == "" || thisComp.explanations == nil {
+		return
+	}
+
+	thisComp.explainMx.Lock()
+	defer thisComp.explainMx.Unlock()
+
+	// the files are compared in parallel, but their traces are output in a stable order
+	traces := thisComp.explanations.children
+	sort.SliceStable(traces, func(i, j int) bool {
+		return traces[i].isFile && traces[j].isFile && traces[i].label < traces[j].label
+	})
+
+	nbWritten := 0
+
+	for _, trace := range traces {
+		// no need to mention the files for which no key has been explained
+		if trace.isFile && len(trace.children) == 0 {
+			continue
+		}
+
+		trace.write(writer, "", true, true)
+		nbWritten++
+	}
+
+	if nbWritten == 0 {
+		fmt.Fprintf(writer, "No key has been built for path '%s'\n", thisComp.Explain)
+	}
+}
@@ -2,6 +2,7 @@ package core
 
 import (
 	"fmt"
+	"io"
 	"os"
 )
 
@@ -9,14 +10,14 @@ import (
 // Here we compare 2 files
 //------------------------------------------------------------------------------
 
-// CompareFiles : getting a diff between 2 files, JSON or XML (for now)
+// CompareFiles : getting a diff between 2 files, JSON or XML (for now); the files can be compressed (gzip, zstd, bzip2)
 func CompareFiles(filepathOne, filepathTwo string, options *ComparisonOptions, doLog bool) (Comparison, error) {
 	// reading the files
 	if doLog {
 		options.Logger.Info("Reading the first file")
 	}
 
-	oneBytes, errOne := os.ReadFile(filepathOne)
+	oneBytes, errOne := readFile(filepathOne)
 	if errOne != nil {
 		panic(fmt.Sprintf("Error while readling file one (%s). Cause: %s", filepathOne, errOne))
 	}
@@ -25,7 +26,7 @@ func CompareFiles(filepathOne, filepathTwo string, options *ComparisonOptions, d
 		options.Logger.Info("Reading the second file")
 	}
 
-	twoBytes, errTwo := os.ReadFile(filepathTwo)
+	twoBytes, errTwo := readFile(filepathTwo)
 	if errTwo != nil {
 		panic(fmt.Sprintf("Error while readling file two (%s). Cause: %s", filepathTwo, errTwo))
 	}
@@ -35,5 +36,30 @@ func CompareFiles(filepathOne, filepathTwo string, options *ComparisonOptions, d
 		options.Logger.Info("Done reading the two files")
 	}
 
-	return compareBytes(oneBytes, twoBytes, options, doLog)
+	comparison, errComp := compareBytes(oneBytes, twoBytes, options, doLog)
+	if errComp != nil {
+		return nil, errComp
+	}
+
+	// removing the known differences
+	return options.reportBaseline(options.Baseline.subtract("", comparison)), nil
+}
+
+// readFile reads the whole given file, decompressing it on the fly if needed
+func readFile(filepath string) ([]byte, error) {
+	file, errOpen := os.Open(filepath)
+	if errOpen != nil {
+		return nil, errOpen
+	}
+
+	defer file.Close()
+
+	reader, errDecomp := decompressReader(filepath, file)
+	if errDecomp != nil {
+		return nil, errDecomp
+	}
+
+	defer reader.Close()
+
+	return io.ReadAll(reader)
 }
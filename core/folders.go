@@ -2,9 +2,6 @@ package core
 
 import (
 	"fmt"
-	"io/ioutil"
-	"path"
-	"sort"
 	"sync"
 	"time"
 )
@@ -13,18 +10,25 @@ import (
 // Here we compare 2 folders
 //------------------------------------------------------------------------------
 
-// CompareFolders : getting a diff between 2 files, JSON or XML (for now)
-//nolint:gocognit,gocyclo,cyclop
+// CompareFolders : getting a diff between 2 folders, containing JSON or XML files (for now); a zip, tar or tar.gz archive
+// can also be used here as a folder
 func CompareFolders(pathOne, pathTwo string, options *ComparisonOptions) (Comparison, error) {
+	return compareSources(newSource(pathOne), newSource(pathTwo), nil, options)
+}
+
+// compareSources : getting a diff between 2 sources of files - i.e. folders, or anything that can be seen as folders;
+// the files are paired by name, or thanks to the given renames
+//nolint:gocognit,gocyclo,cyclop
+func compareSources(sourceOne, sourceTwo fileSource, renames map[string]string, options *ComparisonOptions) (Comparison, error) {
 	// lesssgooooo
 	start := time.Now()
 
-	// the result from comparing the 2 folders
+	// the result from comparing the 2 sources
 	thisComparison := Comparison{}
 
-	// listing the files within the 2 folders
-	_, filesSliceOne, errList1 := getFiles(pathOne, options)
-	filesMapTwo, filesSliceTwo, errList2 := getFiles(pathTwo, options)
+	// listing the files within the 2 sources
+	filesSliceOne, errList1 := sourceOne.listFiles(options)
+	filesSliceTwo, errList2 := sourceTwo.listFiles(options)
 
 	if errList1 != nil {
 		return nil, errList1
@@ -34,11 +38,19 @@ func CompareFolders(pathOne, pathTwo string, options *ComparisonOptions) (Compar
 		return nil, errList2
 	}
 
-	// first, let's keep track of the files we encounter
-	checked := map[string]bool{}
+	// which files should be compared with which ones ?
+	pairs := pairFiles(filesSliceOne, filesSliceTwo, options.getRenames(renames), options)
 
-	// let's count the total number of different files in the union of the two folders
-	nbFilesInitial := len(filesSliceOne)
+	// the files left alone may find a pair, based on their content
+	if options.Pairing != nil && options.Pairing.Similarity > 0 {
+		var errPairing error
+		if pairs, errPairing = pairBySimilarity(pairs, sourceOne, sourceTwo, options); errPairing != nil {
+			return nil, errPairing
+		}
+	}
+
+	// let's count the total number of different files in the union of the two sources
+	nbFilesInitial := len(pairs)
 
 	// we going to handle chunks in parallel
 	chunkSize := nbFilesInitial / options.NParallel
@@ -55,12 +67,15 @@ func CompareFolders(pathOne, pathTwo string, options *ComparisonOptions) (Compar
 	// let's gather the errors in here
 	var errors []error
 
+	// and let's keep track of what happened to each pair of files
+	outcomes := make([]*fileOutcome, nbFilesInitial)
+
 	// let's create as many Go routines as desired
 	for chunkID := 1; chunkID <= options.NParallel; chunkID++ {
 		go func(chunkID int) {
 			defer wg.Done()
 
-			// we're dealing with files with index from chunkID*chunkSize to (chunkID+1)*chunkSize - 1 (which size equals: chunkSize)
+			// we're dealing with pairs with index from chunkID*chunkSize to (chunkID+1)*chunkSize - 1 (which size equals: chunkSize)
 			limit := chunkID * chunkSize
 
 			// but the last chunk will contain a few more elements
@@ -71,35 +86,40 @@ func CompareFolders(pathOne, pathTwo string, options *ComparisonOptions) (Compar
 			// the number of files handled in this routine
 			nbFilesCountedLocal := 0
 
-			// going through the files in the first folder, and comparing with the ones in the second folder
-			for fileNum := (chunkID - 1) * chunkSize; fileNum < limit; fileNum++ {
+			// going through the pairs of files, and comparing the files in each pair
+			for pairNum := (chunkID - 1) * chunkSize; pairNum < limit; pairNum++ {
 				// this is one more file
 				nbFilesCountedLocal++
 
-				// we just need the name of the file we're handling here
-				fileName1 := filesSliceOne[fileNum]
+				// the pair of files we're handling here
+				pair := pairs[pairNum]
 
 				// the comparison object potentially showing some diffs here
 				var compFile1File2 Comparison
 
-				// does this file exist in the 2nd folder ?
-				if !filesMapTwo[fileName1] {
-					// nope, fileName1 cannot be found in the 2nd folder
-					compFile1File2 = one_two(pathOne, "-")
+				switch {
+				case pair.nameTwo == "":
+					// this file cannot be found in the 2nd source
+					compFile1File2 = one_two(sourceOne.getPath(), "-")
 
 					// bit of logging
 					if !options.Silent {
-						options.Logger.Info("File '%s' only exists in dir one!", fileName1)
+						options.Logger.Info("File '%s' only exists in dir one!", pair.nameOne)
 					}
 
-				} else {
-					// if !options.Silent {
-					// 	options.Logger.Info("Just started comparing files: %s", fileName1)
-					// }
+				case pair.nameOne == "":
+					// this is a file that exists in the 2nd source and not the first
+					compFile1File2 = one_two("-", sourceTwo.getPath())
+
+					// bit of logging
+					if !options.Silent {
+						options.Logger.Info("File '%s' only exists in dir two!", pair.nameTwo)
+					}
 
-					// yes, the file exists, so we can compare the 2 files
+				default:
+					// the file exists on both sides, so we can compare the 2 files
 					var errComp error
-					compFile1File2, errComp = CompareFiles(path.Join(pathOne, fileName1), path.Join(pathTwo, fileName1), options, false)
+					compFile1File2, errComp = compareSourcesFiles(sourceOne, sourceTwo, pair, options)
 
 					// we've found an error
 					if errComp != nil {
@@ -107,22 +127,27 @@ func CompareFolders(pathOne, pathTwo string, options *ComparisonOptions) (Compar
 						errors = append(errors, errComp)
 						mx.Unlock()
 
-						break
+						outcomes[pairNum] = &fileOutcome{pair: pair, err: errComp}
+
+						continue
 					}
 				}
 
+				// removing the known differences
+				compFile1File2 = options.Baseline.subtract(pair.key(), compFile1File2)
+
+				// each routine has its own indexes, so no need to lock here
+				outcomes[pairNum] = &fileOutcome{pair: pair, comparison: compFile1File2}
+
 				// we're making a block here for the synchronization
 				if true {
 					// making sure we're not getting race conditions
 					mx.Lock()
 
-					// this file is being checked
-					checked[fileName1] = true
-
 					// adding the diffs, if any
 					if compFile1File2.hasDiffs() {
 						if !options.StopAtFirst || len(thisComparison) == 0 {
-							thisComparison[fileName1] = compFile1File2
+							thisComparison[pair.key()] = compFile1File2
 						}
 					}
 
@@ -131,6 +156,8 @@ func CompareFolders(pathOne, pathTwo string, options *ComparisonOptions) (Compar
 
 					// if required, we stop here
 					if compFile1File2.hasDiffs() && options.StopAtFirst {
+						nbFilesCountedLocal += limit - pairNum - 1
+
 						break
 					}
 				}
@@ -152,61 +179,63 @@ func CompareFolders(pathOne, pathTwo string, options *ComparisonOptions) (Compar
 		panic(fmt.Sprintf("Had %d files, but handled %d files", nbFilesInitial, nbFilesCounted))
 	}
 
-	// now let's iterate over the second folder, because there might be stuff not found in the first folder
-	for _, fileName2 := range filesSliceTwo {
-		// we're considering files that have not been checked yet
-		if !checked[fileName2] {
-			// that's one more file
-			nbFilesInitial++
-			nbFilesCounted++
-
-			// applying the 'StopAtFirst' option if needed
-			if !options.StopAtFirst || len(thisComparison) == 0 {
-				// this is a file that exists in the 2nd folder and not the first, so:
-				thisComparison[fileName2] = one_two("-", pathTwo)
-
-				// bit of logging
-				if !options.Silent {
-					options.Logger.Info("File '%s' only exists in dir two!", fileName2)
-				}
-			}
-
-			if options.StopAtFirst {
-				break
-			}
-		}
+	// some files could not be compared - which is accepted when we're just summarizing
+	if len(errors) > 0 && options.SummaryTop == 0 {
+		return nil, fmt.Errorf("%d file(s) could not be compared. First cause: %s", len(errors), errors[0])
 	}
 
 	if !options.Silent {
 		options.Logger.Info("Finished comparing the two folders in %s; %d diffs over %d files", time.Since(start), len(thisComparison), nbFilesInitial)
 	}
 
-	return thisComparison, nil
+	// the aggregated report is what's desired here
+	if options.SummaryTop > 0 {
+		return options.reportBaseline(summarize(outcomes, options).toComparison()), nil
+	}
+
+	return options.reportBaseline(thisComparison), nil
 }
 
-// returns a directory's list of files as a map
-func getFiles(path string, options *ComparisonOptions) (map[string]bool, []string, error) {
-	// we'll use the filenames as keys
-	filesMap := map[string]bool{}
-	filesSlice := []string{}
+// compareSourcesFiles : reading the 2 files of the given pair, and comparing them
+func compareSourcesFiles(sourceOne, sourceTwo fileSource, pair *filePair, options *ComparisonOptions) (result Comparison, err error) {
+	// the comparison functions may panic; let's not lose the whole run because of 1 file
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			result, err = nil, fmt.Errorf("Error while comparing file '%s'. Cause: %v", pair.key(), recovered)
+		}
+	}()
 
-	// reading the current path
-	fileInfos, errRead := ioutil.ReadDir(path)
-	if errRead != nil {
-		return nil, nil, fmt.Errorf("Error while listing files at path '%s'. Cause: %s", path, errRead)
+	oneBytes, errOne := readSourceFile(sourceOne, pair.nameOne)
+	if errOne != nil {
+		return nil, errOne
 	}
 
-	// let's list the files
-	for _, fileInfo := range fileInfos {
-		if filename := fileInfo.Name(); !options.Ignored[filename] {
-			filesMap[filename] = true
+	twoBytes, errTwo := readSourceFile(sourceTwo, pair.nameTwo)
+	if errTwo != nil {
+		return nil, errTwo
+	}
 
-			filesSlice = append(filesSlice, filename)
-		}
+	comparison, errComp := compareBytes(oneBytes, twoBytes, options.forExplainedFile(pair.key()), false)
+	if errComp != nil {
+		return nil, fmt.Errorf("Error while comparing file '%s'. Cause: %s", pair.key(), errComp)
+	}
+
+	return comparison, nil
+}
+
+// readSourceFile reads a file from the given source, decompressing it if needed
+func readSourceFile(source fileSource, name string) ([]byte, error) {
+	fileBytes, errRead := source.readFile(name)
+	if errRead != nil {
+		return nil, errRead
 	}
 
-	// let's sort the file names
-	sort.Strings(filesSlice)
+	return decompressBytes(name, fileBytes)
+}
 
-	return filesMap, filesSlice, nil
+// fileOutcome : what happened when handling a pair of files
+type fileOutcome struct {
+	pair       *filePair  // the compared files
+	comparison Comparison // the result of their comparison
+	err        error      // the error that prevented the comparison, if any
 }
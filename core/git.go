@@ -0,0 +1,189 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path"
+	"sort"
+	"strings"
+)
+
+//------------------------------------------------------------------------------
+// Here we compare 2 revisions of a local git repository, without checking
+// them out, by reading the files right from the git objects
+//------------------------------------------------------------------------------
+
+// CompareGitRevisions : getting a diff between the JSON or XML files found, within the given sub path (relative to the repository's root),
+// at 2 revisions (commits, tags, branches, etc.) of a local git repository; the files renamed between the 2 revisions are compared with each other
+func CompareGitRevisions(repoPath, revisionOne, revisionTwo, subPath string, options *ComparisonOptions) (Comparison, error) {
+	// we're working from the root of the repository
+	rootBytes, errRoot := runGit(repoPath, "rev-parse", "--show-toplevel")
+	if errRoot != nil {
+		return nil, errRoot
+	}
+
+	root := strings.TrimSpace(string(rootBytes))
+	subPath = cleanGitPath(subPath)
+
+	// the renamed files
+	renames, errRenames := getGitRenames(root, revisionOne, revisionTwo, subPath)
+	if errRenames != nil {
+		return nil, errRenames
+	}
+
+	if !options.Silent {
+		options.Logger.Info("Found %d renamed file(s) between revisions '%s' and '%s'", len(renames), revisionOne, revisionTwo)
+	}
+
+	return compareSources(
+		&gitSource{repo: root, revision: revisionOne, subPath: subPath},
+		&gitSource{repo: root, revision: revisionTwo, subPath: subPath},
+		renames, options)
+}
+
+//------------------------------------------------------------------------------
+// A git revision, seen as a folder
+//------------------------------------------------------------------------------
+
+type gitSource struct {
+	repo     string // the root of the git repository
+	revision string // the revision we're reading the files from
+	subPath  string // the folder we're considering within the repository - or a single file
+	isFile   bool   // true if the sub path designates a file
+}
+
+func (thisGit *gitSource) getPath() string {
+	return thisGit.revision + ":" + thisGit.subPath
+}
+
+// listing the files, recursively, found within the sub path, at this source's revision
+func (thisGit *gitSource) listFiles(options *ComparisonOptions) ([]string, error) {
+	args := []string{"ls-tree", "-r", "-z", "--full-tree", "--name-only", thisGit.revision}
+	if thisGit.subPath != "" {
+		args = append(args, "--", thisGit.subPath)
+	}
+
+	output, errList := runGit(thisGit.repo, args...)
+	if errList != nil {
+		return nil, errList
+	}
+
+	filesSlice := []string{}
+
+	for _, fullName := range splitGitOutput(output) {
+		if fullName == thisGit.subPath {
+			thisGit.isFile = true
+		}
+
+		if filename := thisGit.relative(fullName); !options.isIgnored(filename) {
+			filesSlice = append(filesSlice, filename)
+		}
+	}
+
+	// let's sort the file names
+	sort.Strings(filesSlice)
+
+	return filesSlice, nil
+}
+
+func (thisGit *gitSource) readFile(name string) ([]byte, error) {
+	// the sub path may be the file itself
+	if thisGit.isFile && name == path.Base(thisGit.subPath) {
+		return runGit(thisGit.repo, "cat-file", "blob", thisGit.revision+":"+thisGit.subPath)
+	}
+
+	return runGit(thisGit.repo, "cat-file", "blob", thisGit.revision+":"+path.Join(thisGit.subPath, name))
+}
+
+// relative returns the given file name, relatively to this source's sub path
+func (thisGit *gitSource) relative(fullName string) string {
+	if thisGit.subPath == "" {
+		return fullName
+	}
+
+	if fullName == thisGit.subPath { // the sub path designates a file
+		return path.Base(fullName)
+	}
+
+	return strings.TrimPrefix(fullName, thisGit.subPath+"/")
+}
+
+//------------------------------------------------------------------------------
+// Utils
+//------------------------------------------------------------------------------
+
+// getGitRenames returns the files renamed between the 2 given revisions, as a map: old name -> new name (relatively to the sub path)
+func getGitRenames(repo, revisionOne, revisionTwo, subPath string) (map[string]string, error) {
+	args := []string{"diff", "--name-status", "-z", "--find-renames", revisionOne, revisionTwo}
+	if subPath != "" {
+		args = append(args, "--", subPath)
+	}
+
+	output, errDiff := runGit(repo, args...)
+	if errDiff != nil {
+		return nil, errDiff
+	}
+
+	// with the '-z' option, we get: status NUL path NUL, or - for renamings & copies: status NUL old path NUL new path NUL
+	source := &gitSource{subPath: subPath}
+	renames := map[string]string{}
+	fields := splitGitOutput(output)
+
+	for index := 0; index < len(fields); index++ {
+		switch fields[index][0] {
+		case 'R':
+			//nolint:gomnd
+			if index+2 < len(fields) {
+				renames[source.relative(fields[index+1])] = source.relative(fields[index+2])
+			}
+
+			index += 2
+		case 'C':
+			index += 2
+		default:
+			index++
+		}
+	}
+
+	return renames, nil
+}
+
+// runGit runs a git command on the given repository, and returns its output
+func runGit(repo string, args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+
+	cmd := exec.Command("git", append([]string{"-C", repo}, args...)...)
+	cmd.Stderr = &stderr
+
+	output, errRun := cmd.Output()
+	if errRun != nil {
+		return nil, fmt.Errorf("Error while running 'git %s' on repository '%s'. Cause: %s (%s)",
+			strings.Join(args, " "), repo, errRun, strings.TrimSpace(stderr.String()))
+	}
+
+	return output, nil
+}
+
+// splitGitOutput splits an output obtained with the '-z' option
+func splitGitOutput(output []byte) []string {
+	fields := []string{}
+
+	for _, field := range strings.Split(string(output), "\x00") {
+		if field != "" {
+			fields = append(fields, field)
+		}
+	}
+
+	return fields
+}
+
+// cleanGitPath makes the given path usable within git pathspecs, and revision paths
+func cleanGitPath(subPath string) string {
+	subPath = strings.Trim(path.Clean("/"+subPath), "/")
+	if subPath == "." {
+		return ""
+	}
+
+	return subPath
+}
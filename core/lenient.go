@@ -0,0 +1,160 @@
+package core
+
+import (
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+//------------------------------------------------------------------------------
+// Here we compare values leniently, for the producers that are loose with the
+// types: "12.50" is then equal to 12.5, "true" to true, and "" to null
+//------------------------------------------------------------------------------
+
+// isLenient tells if the values should be compared leniently at this ID param's path; the setting is inherited from the parent ID params,
+// and from the options in the end
+func (thisParam *IdentificationParameter) isLenient(options *ComparisonOptions) bool {
+	for param := thisParam; param != nil; param = param.parent {
+		if param.Lenient != nil {
+			return *param.Lenient
+		}
+	}
+
+	return options.Lenient
+}
+
+// compareProperty compares the values of a property of 2 objects, handled with the given ID param; the property's own ID param,
+// if any, is `nextIdParam`; the property may be missing in 1 of the objects
+func compareProperty(root1, root2 *JsonEntity, idParam, nextIdParam *IdentificationParameter, obj1, obj2 interface{}, present1, present2 bool,
+	options *ComparisonOptions, currentPathValue string) (Comparison, error) {
+	// the property's own settings prevail, but the undeclared properties depend on their parent
+	lenientParam := nextIdParam
+	if lenientParam == nil {
+		lenientParam = idParam
+
+		// a custom comparator can be registered for an undeclared property
+		if obj1 != nil && obj2 != nil && options.comparators != nil {
+			if comparator := options.comparators[strings.TrimPrefix(getPropertyParamPath(idParam, currentPathValue), ".")]; comparator != nil {
+				return applyComparator(comparator, obj1, obj2), nil
+			}
+		}
+	}
+
+	// the null, empty or missing values are handled according to the policy at this path
+	obj1, obj2, comparison, compared := compareNulls(lenientParam, nextIdParam, obj1, obj2, present1, present2, options, currentPathValue)
+	if compared {
+		return comparison, nil
+	}
+
+	// the strings can be equal once normalized
+	if compareNormalized(lenientParam.getNormalization(options), obj1, obj2) {
+		return nodif(), nil
+	}
+
+	if lenientParam.isLenient(options) {
+		// the custom and built-in comparators prevail over the coercion...
+		if comparison, compared := options.compareCustom(nextIdParam, obj1, obj2, currentPathValue); compared {
+			return comparison, nil
+		}
+
+		if comparison, compared := nextIdParam.compareBuiltin(obj1, obj2); compared {
+			return comparison, nil
+		}
+
+		// and so do the embedded payloads, to compare as data trees
+		if comparison, compared, errComp := compareEmbedded(nextIdParam, obj1, obj2, options, currentPathValue); compared {
+			return comparison, errComp
+		}
+
+		if comparison, coerced := compareCoerced(obj1, obj2, options, currentPathValue); coerced {
+			return comparison, nil
+		}
+	}
+
+	return compareObjects(root1, root2, nextIdParam, obj1, obj2, options, currentPathValue)
+}
+
+// compareCoerced compares 2 simple values once coerced to the same type; returns false if there's no coercion to perform
+func compareCoerced(obj1, obj2 interface{}, options *ComparisonOptions, currentPathValue string) (Comparison, bool) {
+	// only the simple values can be coerced
+	if !isSimpleValue(obj1) || !isSimpleValue(obj2) {
+		return nil, false
+	}
+
+	// no need to coerce anything if the values are the same, type included
+	if reflect.TypeOf(obj1) == reflect.TypeOf(obj2) && obj1 == obj2 {
+		return nil, false
+	}
+
+	if coerced1, coerced2 := coerceValue(obj1), coerceValue(obj2); coerced1 == coerced2 {
+		if !options.Silent {
+			options.Logger.Info("Leniently considered as equal at path '%s': %#v VS %#v", currentPathValue, obj1, obj2)
+		}
+
+		// the leniency implied by comparing XML with JSON would make every value coerced here
+		if options.implicitLenient {
+			return nodif(), true
+		}
+
+		return coerced(obj1, obj2), true
+	}
+
+	// the values are different, even when coerced
+	switch {
+	case obj1 == nil:
+		return two(obj2), true
+	case obj2 == nil:
+		return one(obj1), true
+	default:
+		return one_two(obj1, obj2), true
+	}
+}
+
+// isSimpleValue tells if the given value is a string, a number, a boolean, or null
+func isSimpleValue(obj interface{}) bool {
+	switch obj.(type) {
+	case nil, string, float64, json.Number, bool:
+		return true
+	}
+
+	return false
+}
+
+// coercedNumber : a number, once coerced
+type coercedNumber string
+
+// coerceValue returns the number, boolean or null that a simple value stands for; the other strings are kept as is; the numbers are
+// written in a canonical way, to be compared exactly
+func coerceValue(obj interface{}) interface{} {
+	if number, isNumber := getExactNumber(obj); isNumber {
+		return coercedNumber(number.RatString())
+	}
+
+	str, isString := obj.(string)
+	if !isString {
+		return obj
+	}
+
+	str = strings.TrimSpace(str)
+
+	if str == "" {
+		return nil
+	}
+
+	if _, errParse := strconv.ParseFloat(str, 64); errParse == nil {
+		if number, isNumber := new(big.Rat).SetString(str); isNumber {
+			return coercedNumber(number.RatString())
+		}
+	}
+
+	switch strings.ToLower(str) {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+
+	return str
+}
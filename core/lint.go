@@ -0,0 +1,89 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+//------------------------------------------------------------------------------
+// Here we check the ID params against a sample file, to find the '_for' paths
+// that never match anything - usually because of a typo in a property name
+//------------------------------------------------------------------------------
+
+// LintIdParams returns the problems found with the ID params when applied to the given sample file
+func (thisComp *ComparisonOptions) LintIdParams(sampleFilepath string) ([]string, error) {
+	fileBytes, errRead := readFile(sampleFilepath)
+	if errRead != nil {
+		return nil, fmt.Errorf("Error while reading the sample file '%s'. Cause: %s", sampleFilepath, errRead)
+	}
+
+	sample, errUnmarsh := unmarshalBytes(fileBytes, thisComp.sniffFileType(fileBytes), thisComp)
+	if errUnmarsh != nil {
+		return nil, fmt.Errorf("Error while unmarshalling the sample file '%s'. Cause: %s", sampleFilepath, errUnmarsh)
+	}
+
+	problems := []string{}
+
+	lintForPaths(thisComp.IdParams, []interface{}{sample}, "", &problems)
+
+	return problems, nil
+}
+
+// lintForPaths checks, for each of the '_for' paths of the given ID param, that it leads to some values in the sample
+func lintForPaths(idParam *IdentificationParameter, values []interface{}, pointer string, problems *[]string) {
+	if idParam == nil {
+		return
+	}
+
+	// sorting the paths, for a stable output
+	keys := []string{}
+	for key := range idParam.For {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		subPointer := pointer + "/_for/" + escapePointerToken(key)
+		subValues := []interface{}{}
+
+		for _, value := range values {
+			if valueMap, isMap := value.(map[string]interface{}); isMap {
+				if child, exists := valueMap[key]; exists {
+					subValues = append(subValues, flattenSampleValue(child)...)
+				}
+			}
+		}
+
+		if len(subValues) == 0 {
+			*problems = append(*problems, fmt.Sprintf("%s: the path '%s' is never reached in the sample file", subPointer, strings.TrimPrefix(idParam.For[key].toString(), ".")))
+
+			continue
+		}
+
+		lintForPaths(idParam.For[key], subValues, subPointer, problems)
+	}
+
+	// the conditional ID params can have their own '_for' paths too
+	for index, condition := range idParam.When {
+		lintForPaths(&condition.IdentificationParameter, values, fmt.Sprintf("%s/when/%d", pointer, index), problems)
+	}
+}
+
+// flattenSampleValue returns the elements of a value if it's an array, or the value itself
+func flattenSampleValue(value interface{}) []interface{} {
+	switch typedValue := value.(type) {
+	case []interface{}:
+		return typedValue
+	case []map[string]interface{}:
+		result := make([]interface{}, len(typedValue))
+		for i, element := range typedValue {
+			result[i] = element
+		}
+
+		return result
+	default:
+		return []interface{}{value}
+	}
+}
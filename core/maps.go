@@ -8,9 +8,10 @@ import "sort"
 //------------------------------------------------------------------------------
 
 type JsonEntity struct {
-	parent *JsonEntity
-	values map[string]interface{}
-	counts map[string]int
+	parent    *JsonEntity
+	values    map[string]interface{}
+	counts    map[string]int
+	positions map[string]int
 }
 
 func entity(obj map[string]interface{}) *JsonEntity {
@@ -64,7 +65,7 @@ func compareJsonEntities(idParam *IdentificationParameter, ent1, ent2 *JsonEntit
 			nextPathValue := currentPathValue + ">" + key1
 
 			// what's in the 2nd map ?
-			obj2 := ent2.values[key1]
+			obj2, present2 := ent2.values[key1]
 
 			// what's the next ID parameter associated with the current object ?
 			nextIdParam := idParam
@@ -75,7 +76,7 @@ func compareJsonEntities(idParam *IdentificationParameter, ent1, ent2 *JsonEntit
 			}
 
 			// obj1 and obj2 should be compared
-			compObj1Obj2, errComp := compareObjects(ent1, ent2, nextIdParam, obj1, obj2, options, nextPathValue)
+			compObj1Obj2, errComp := compareProperty(ent1, ent2, idParam, nextIdParam, obj1, obj2, true, present2, options, nextPathValue)
 			if errComp != nil {
 				return nil, errComp
 			}
@@ -111,13 +112,15 @@ func compareJsonEntities(idParam *IdentificationParameter, ent1, ent2 *JsonEntit
 			}
 
 			// at this point, obj1 does not exist for this key...
-			compObj1Obj2, errComp := compareObjects(ent1, ent2, nextIdParam, ent1.values[key2], ent2.values[key2], options, nextPathValue)
+			compObj1Obj2, errComp := compareProperty(ent1, ent2, idParam, nextIdParam, ent1.values[key2], ent2.values[key2], false, true, options, nextPathValue)
 			if errComp != nil {
 				return nil, errComp
 			}
 
-			// ... so we're sure to have a difference here
-			thisComparison[key2] = compObj1Obj2
+			// ... so we usually have a difference here - but not if obj2 is null, or empty, and considered as missing
+			if compObj1Obj2.hasDiffs() {
+				thisComparison[key2] = compObj1Obj2
+			}
 		}
 	}
 
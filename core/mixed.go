@@ -0,0 +1,129 @@
+package core
+
+import (
+	"bytes"
+	"strings"
+)
+
+//------------------------------------------------------------------------------
+// Here we compare an XML file with a JSON file holding the same data, e.g.
+// when migrating a SOAP interface to a JSON API
+//------------------------------------------------------------------------------
+
+// the xml2map conventions - the default ones for our own decoder
+const (
+	xmlATTR  = "@"     // the prefix of the attributes
+	xmlTEXT  = "#text" // the key of the text of an element having attributes or children
+	xmlXMLNS = "xmlns" // the namespace declarations, which are not data
+)
+
+// sniffFileType determines the type of a file from its content - unless the files have been declared as XML
+func (thisComp *ComparisonOptions) sniffFileType(data []byte) FileType {
+	if thisComp.IsXml {
+		return FileTypeXML
+	}
+
+	// skipping a possible BOM
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '<' {
+		return FileTypeXML
+	}
+
+	return FileTypeJSON
+}
+
+// mapXmlDocument transforms an XML document, as decoded by decodeXml, so that it looks like the given JSON document: the root element is
+// removed if the JSON document does not have it, and then the values are mapped with mapXmlValue; also returns the ID params to use
+// for comparing the 2 documents
+func (thisComp *ComparisonOptions) mapXmlDocument(xmlDoc map[string]interface{}, jsonDoc interface{}, idParams *IdentificationParameter) (interface{},
+	*IdentificationParameter) {
+	jsonMap, isMap := jsonDoc.(map[string]interface{})
+
+	// an XML document has exactly 1 root element
+	for rootLabel, root := range xmlDoc {
+		rootName := getLocalName(rootLabel)
+
+		if _, jsonHasRoot := jsonMap[rootName]; isMap && !jsonHasRoot {
+			// the ID params may have been written for the XML document, or for the JSON document
+			if idParams != nil && idParams.For[rootName] != nil {
+				return thisComp.mapXmlValue(root, idParams.For[rootName]), idParams.For[rootName]
+			}
+
+			return thisComp.mapXmlValue(root, idParams), idParams
+		}
+	}
+
+	return thisComp.mapXmlValue(xmlDoc, idParams), idParams
+}
+
+// mapXmlValue transforms an XML value, as decoded by decodeXml, into its JSON equivalent: the attributes lose their prefix, the names lose
+// their namespace, the elements with only some text become this text, and the elements that the ID params identify are put into arrays,
+// even when single
+func (thisComp *ComparisonOptions) mapXmlValue(value interface{}, idParam *IdentificationParameter) interface{} {
+	attrPrefix, textKey := thisComp.getXmlAttrPrefix(), thisComp.getXmlTextKey()
+
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		result := map[string]interface{}{}
+
+		for key, child := range typedValue {
+			name := strings.TrimPrefix(key, attrPrefix)
+
+			// the namespace declarations are not data
+			if name != key && (name == xmlXMLNS || strings.HasPrefix(name, xmlXMLNS+":")) {
+				continue
+			}
+
+			// JSON has no namespaces
+			name = getLocalName(name)
+
+			var childParam *IdentificationParameter
+			if idParam != nil {
+				childParam = idParam.For[name]
+			}
+
+			mappedChild := thisComp.mapXmlValue(child, childParam)
+
+			// a single element, where an array is expected
+			if _, isSlice := mappedChild.([]interface{}); !isSlice && (childParam.identifiesElements() || childParam.matchesBySimilarity()) {
+				mappedChild = []interface{}{mappedChild}
+			}
+
+			result[name] = mappedChild
+		}
+
+		// an element with only some text
+		if text, hasText := result[textKey]; hasText && len(result) == 1 {
+			return text
+		}
+
+		return result
+
+	case []map[string]interface{}:
+		result := make([]interface{}, len(typedValue))
+		for i, element := range typedValue {
+			result[i] = thisComp.mapXmlValue(element, idParam)
+		}
+
+		return result
+
+	case []string:
+		result := make([]interface{}, len(typedValue))
+		for i, element := range typedValue {
+			result[i] = element
+		}
+
+		return result
+
+	case []interface{}:
+		result := make([]interface{}, len(typedValue))
+		for i, element := range typedValue {
+			result[i] = thisComp.mapXmlValue(element, idParam)
+		}
+
+		return result
+	}
+
+	return value
+}
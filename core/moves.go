@@ -0,0 +1,103 @@
+package core
+
+import "sort"
+
+//------------------------------------------------------------------------------
+// Here we detect the elements of keyed arrays that have changed position, for
+// the arrays where the order matters
+//------------------------------------------------------------------------------
+
+// the properties of a move
+const (
+	moveFROM = "from" // the position of the element in the first array
+	moveTO   = "to"   // the position of the element in the second array
+)
+
+// detectsMoves tells if the position changes of the array elements at this ID param's path should be reported
+func (thisParam *IdentificationParameter) detectsMoves() bool {
+	return thisParam != nil && thisParam.Moves
+}
+
+// setPosition records the position, in its original array, of the element with the given key
+func (thisEntity *JsonEntity) setPosition(key string, position int) {
+	if thisEntity.positions == nil {
+		thisEntity.positions = map[string]int{}
+	}
+
+	// with duplicates, the first position is kept
+	if _, exists := thisEntity.positions[key]; !exists {
+		thisEntity.positions[key] = position
+	}
+}
+
+// addMoves reports, in the given comparison of 2 keyed arrays, the elements that have moved; when an element is inserted or removed,
+// the other elements are shifted, but not moved: the moved elements are the ones outside of the longest sequence of elements that
+// have kept their relative order
+func addMoves(comparison Comparison, ent1, ent2 *JsonEntity) Comparison {
+	// the elements found in both arrays, in the order of the first array
+	commonKeys := []string{}
+
+	for key := range ent1.positions {
+		if _, inTwo := ent2.positions[key]; inTwo {
+			commonKeys = append(commonKeys, key)
+		}
+	}
+
+	sort.Slice(commonKeys, func(i, j int) bool { return ent1.positions[commonKeys[i]] < ent1.positions[commonKeys[j]] })
+
+	// the positions in the second array
+	positions2 := make([]int, len(commonKeys))
+	for i, key := range commonKeys {
+		positions2[i] = ent2.positions[key]
+	}
+
+	kept := getLongestIncreasingSubsequence(positions2)
+
+	for i, key := range commonKeys {
+		if kept[i] {
+			continue
+		}
+
+		node, isMap := asMap(comparison[key])
+		if !isMap {
+			node = Comparison{}
+		}
+
+		node[markerMOVED] = map[string]interface{}{moveFROM: ent1.positions[key], moveTO: ent2.positions[key]}
+		comparison[key] = Comparison(node)
+	}
+
+	return comparison
+}
+
+// getLongestIncreasingSubsequence tells, for each of the given values, if it's part of their longest increasing subsequence
+func getLongestIncreasingSubsequence(values []int) []bool {
+	// tails[k] = the index of the smallest value ending an increasing subsequence of length k+1
+	tails := []int{}
+	previous := make([]int, len(values))
+
+	for i, value := range values {
+		length := sort.Search(len(tails), func(k int) bool { return values[tails[k]] >= value })
+
+		previous[i] = -1
+		if length > 0 {
+			previous[i] = tails[length-1]
+		}
+
+		if length == len(tails) {
+			tails = append(tails, i)
+		} else {
+			tails[length] = i
+		}
+	}
+
+	result := make([]bool, len(values))
+
+	if len(tails) > 0 {
+		for i := tails[len(tails)-1]; i >= 0; i = previous[i] {
+			result[i] = true
+		}
+	}
+
+	return result
+}
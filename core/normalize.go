@@ -0,0 +1,199 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+//------------------------------------------------------------------------------
+// Here we normalize the strings before comparing them, or building keys with
+// them, since many differences are just cosmetic: trailing spaces, case,
+// Unicode normalization forms...
+//------------------------------------------------------------------------------
+
+// StringNormalization : how to normalize the strings, globally or at a given path - and below, unless specified otherwise; the steps are
+// applied in this order: Unicode, whitespace, case, then the replacements
+type StringNormalization struct {
+	Unicode    string         `json:"unicode,omitempty"`    // the Unicode normalization form: "NFC", "NFD", "NFKC" or "NFKD"
+	Whitespace string         `json:"whitespace,omitempty"` // "trim" to remove the leading and trailing whitespace, "collapse" to also turn any sequence of whitespace into 1 space
+	Case       string         `json:"case,omitempty"`       // "fold" to ignore the case, or "lower", or "upper"
+	Replace    []*ReplaceRule `json:"replace,omitempty"`    // regular expressions to replace, e.g. {"pattern": "^0+", "with": ""} to remove the leading zeros
+}
+
+// ReplaceRule : replacing the matches of a regular expression
+type ReplaceRule struct {
+	Pattern string `json:"pattern"` // the regular expression
+	With    string `json:"with"`    // the replacement, which can refer to the groups of the expression, e.g. "$1"
+
+	// technical properties
+	patternRegexp *regexp.Regexp
+}
+
+// the Unicode normalization forms
+var unicodeForms = map[string]norm.Form{"NFC": norm.NFC, "NFD": norm.NFD, "NFKC": norm.NFKC, "NFKD": norm.NFKD}
+
+// getNormalizationFromString reads the global normalization of the strings, given as JSON, or as the path to a JSON file
+func (thisComp *ComparisonOptions) getNormalizationFromString() *StringNormalization {
+	if thisComp.NormalizationString == "" {
+		return nil
+	}
+
+	normalization := &StringNormalization{}
+
+	if errUnmarsh := json.Unmarshal([]byte(getStringOrFileContent(thisComp.NormalizationString)), normalization); errUnmarsh != nil {
+		panic(fmt.Errorf("not a valid string normalization (%s)", errUnmarsh))
+	}
+
+	if errCheck := normalization.resolve(); errCheck != nil {
+		panic(fmt.Errorf("not a valid string normalization (%s)", errCheck))
+	}
+
+	return normalization
+}
+
+// resolve checks these settings, and compiles the regular expressions
+func (thisNormalization *StringNormalization) resolve() error {
+	if _, known := unicodeForms[thisNormalization.Unicode]; thisNormalization.Unicode != "" && !known {
+		return fmt.Errorf("unknown Unicode normalization form: '%s'", thisNormalization.Unicode)
+	}
+
+	switch thisNormalization.Whitespace {
+	case "", xmlSpaceTRIM, xmlSpaceCOLLAPSE:
+	default:
+		return fmt.Errorf("unknown way to handle the whitespace: '%s'", thisNormalization.Whitespace)
+	}
+
+	switch thisNormalization.Case {
+	case "", "fold", "lower", "upper":
+	default:
+		return fmt.Errorf("unknown way to handle the case: '%s'", thisNormalization.Case)
+	}
+
+	for _, rule := range thisNormalization.Replace {
+		var errCompile error
+		if rule.patternRegexp, errCompile = regexp.Compile(rule.Pattern); errCompile != nil {
+			return fmt.Errorf("invalid pattern '%s': %s", rule.Pattern, errCompile)
+		}
+	}
+
+	return nil
+}
+
+// override returns these settings, overridden with the given ones, if any; the replacements add up
+func (thisNormalization *StringNormalization) override(overriding *StringNormalization) *StringNormalization {
+	if thisNormalization == nil || overriding == nil {
+		if overriding == nil {
+			return thisNormalization
+		}
+
+		return overriding
+	}
+
+	result := *thisNormalization
+
+	if overriding.Unicode != "" {
+		result.Unicode = overriding.Unicode
+	}
+
+	if overriding.Whitespace != "" {
+		result.Whitespace = overriding.Whitespace
+	}
+
+	if overriding.Case != "" {
+		result.Case = overriding.Case
+	}
+
+	result.Replace = append(append([]*ReplaceRule{}, thisNormalization.Replace...), overriding.Replace...)
+
+	return &result
+}
+
+// apply normalizes the given string
+func (thisNormalization *StringNormalization) apply(str string) string {
+	if thisNormalization == nil {
+		return str
+	}
+
+	if form, hasForm := unicodeForms[thisNormalization.Unicode]; hasForm {
+		str = form.String(str)
+	}
+
+	switch thisNormalization.Whitespace {
+	case xmlSpaceTRIM:
+		str = strings.TrimSpace(str)
+	case xmlSpaceCOLLAPSE:
+		str = strings.Join(strings.Fields(str), " ")
+	}
+
+	switch thisNormalization.Case {
+	case "fold":
+		str = cases.Fold().String(str)
+	case "lower":
+		str = strings.ToLower(str)
+	case "upper":
+		str = strings.ToUpper(str)
+	}
+
+	for _, rule := range thisNormalization.Replace {
+		str = rule.patternRegexp.ReplaceAllString(str, rule.With)
+	}
+
+	return str
+}
+
+// resolveNormalization computes the normalization to use at this ID param's path, and below, from the one used above
+func (thisParam *IdentificationParameter) resolveNormalization(above *StringNormalization) error {
+	if thisParam.Normalize != nil {
+		if errCheck := thisParam.Normalize.resolve(); errCheck != nil {
+			return fmt.Errorf("at path '%s': %s", thisParam.toString(), errCheck)
+		}
+	}
+
+	thisParam.normalization = above.override(thisParam.Normalize)
+
+	for _, subParam := range thisParam.For {
+		if errResolve := subParam.resolveNormalization(thisParam.normalization); errResolve != nil {
+			return errResolve
+		}
+	}
+
+	for _, condition := range thisParam.When {
+		if errResolve := condition.resolveNormalization(thisParam.normalization); errResolve != nil {
+			return errResolve
+		}
+	}
+
+	for _, looked := range thisParam.Look {
+		if errResolve := looked.resolveNormalization(thisParam.normalization); errResolve != nil {
+			return errResolve
+		}
+	}
+
+	return nil
+}
+
+// getNormalization returns the normalization of the strings at this ID param's path - the global one for the undeclared paths
+func (thisParam *IdentificationParameter) getNormalization(options *ComparisonOptions) *StringNormalization {
+	if thisParam == nil {
+		return options.Normalization
+	}
+
+	return thisParam.normalization
+}
+
+// compareNormalized tells if 2 values are equal strings, once normalized
+func compareNormalized(normalization *StringNormalization, obj1, obj2 interface{}) bool {
+	if normalization == nil {
+		return false
+	}
+
+	str1, isString1 := obj1.(string)
+	str2, isString2 := obj2.(string)
+
+	return isString1 && isString2 && normalization.apply(str1) == normalization.apply(str2)
+}
@@ -0,0 +1,104 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+)
+
+//------------------------------------------------------------------------------
+// Here we handle the null, empty or missing values: depending on the producers,
+// a null value and a missing one may mean the same thing - or not - and so may
+// an empty string, array or object
+//------------------------------------------------------------------------------
+
+// the policies for the null, empty or missing values
+const (
+	nullsSTRICT            = "strict"               // a null value differs from a missing one, and these values are reported with dedicated markers
+	nullsEQUALMISSING      = "null-equals-missing"  // a null value is the same as a missing one - the default
+	nullsEMPTYEQUALMISSING = "empty-equals-missing" // a null value, an empty string, array or object are all the same as a missing value
+	nullsDEFAULT           = nullsEQUALMISSING
+	nullsPOLICIES          = nullsSTRICT + ", " + nullsEQUALMISSING + ", " + nullsEMPTYEQUALMISSING
+)
+
+// checkNullPolicy panics if the given policy is unknown
+func checkNullPolicy(policy string) {
+	switch policy {
+	case "", nullsSTRICT, nullsEQUALMISSING, nullsEMPTYEQUALMISSING:
+	default:
+		panic(fmt.Errorf("unknown policy for the null values: '%s'; expected one of: %s", policy, nullsPOLICIES))
+	}
+}
+
+// getNullPolicy returns how to handle the null, empty or missing values at this ID param's path; the setting is inherited from the parent
+// ID params, and from the options in the end
+func (thisParam *IdentificationParameter) getNullPolicy(options *ComparisonOptions) string {
+	for param := thisParam; param != nil; param = param.parent {
+		if param.Nulls != "" {
+			return param.Nulls
+		}
+	}
+
+	if options.NullPolicy != "" {
+		return options.NullPolicy
+	}
+
+	return nullsDEFAULT
+}
+
+// compareNulls compares the values of a property of 2 objects, where it may be missing, according to the policy at this path; the values can be
+// replaced with null, if they're empty and considered as missing; returns true if the comparison is done
+func compareNulls(policyParam, nextIdParam *IdentificationParameter, obj1, obj2 interface{}, present1, present2 bool, options *ComparisonOptions,
+	currentPathValue string) (interface{}, interface{}, Comparison, bool) {
+	switch policyParam.getNullPolicy(options) {
+	case nullsSTRICT:
+		switch {
+		case obj1 == nil && obj2 == nil:
+			// a null value VS a missing one
+			if present1 != present2 {
+				if present1 {
+					return obj1, obj2, one_two(markerNULL, markerMISSING), true
+				}
+
+				return obj1, obj2, one_two(markerMISSING, markerNULL), true
+			}
+
+		case obj1 == nil && present1:
+			// a null value VS a non-null one
+			return obj1, obj2, one_two(markerNULL, nextIdParam.getAliasOrValue(obj2, currentPathValue, options)), true
+
+		case obj2 == nil && present2:
+			// a non-null value VS a null one
+			return obj1, obj2, one_two(nextIdParam.getAliasOrValue(obj1, currentPathValue, options), markerNULL), true
+		}
+
+	case nullsEMPTYEQUALMISSING:
+		if isEmptyValue(obj1) {
+			obj1 = nil
+		}
+
+		if isEmptyValue(obj2) {
+			obj2 = nil
+		}
+	}
+
+	return obj1, obj2, nil, false
+}
+
+// getAliasOrValue returns the alias of the given object, if there's one, or the object itself
+func (thisParam *IdentificationParameter) getAliasOrValue(obj interface{}, currentPathValue string, options *ComparisonOptions) interface{} {
+	if alias := thisParam.getAlias(obj, currentPathValue, options); alias != "" {
+		return alias
+	}
+
+	return obj
+}
+
+// isEmptyValue tells if the given value is an empty string, array or object
+func isEmptyValue(obj interface{}) bool {
+	switch value := reflect.ValueOf(obj); value.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map:
+		return value.Len() == 0
+	}
+
+	return false
+}
@@ -0,0 +1,46 @@
+package core
+
+import "testing"
+
+func TestCompareNulls(t *testing.T) {
+	cases := []struct {
+		policy       string
+		data1, data2 string
+		expected     string
+	}{
+		{nullsSTRICT, `{"a":null}`, `{}`, `{"a":{"_one_":"_null_","_two_":"_missing_"}}`},
+		{nullsSTRICT, `{}`, `{"a":null}`, `{"a":{"_one_":"_missing_","_two_":"_null_"}}`},
+		{nullsSTRICT, `{"a":null}`, `{"a":2}`, `{"a":{"_one_":"_null_","_two_":2}}`},
+		{nullsSTRICT, `{"a":2}`, `{"a":null}`, `{"a":{"_one_":2,"_two_":"_null_"}}`},
+		{nullsSTRICT, `{"a":null}`, `{"a":null}`, `{}`},
+		{nullsSTRICT, `{}`, `{"a":2}`, `{"a":{"_new_":2}}`},
+		{nullsEQUALMISSING, `{"a":null}`, `{}`, `{}`},
+		{nullsEQUALMISSING, `{}`, `{"a":null}`, `{}`},
+		{nullsEQUALMISSING, `{"a":""}`, `{}`, `{"a":{"_del_":""}}`},
+		{nullsEMPTYEQUALMISSING, `{"a":"","b":[],"c":{}}`, `{"a":null}`, `{}`},
+		{nullsEMPTYEQUALMISSING, `{"a":""}`, `{"a":"x"}`, `{"a":{"_new_":"x"}}`},
+		{"", `{"a":null}`, `{}`, `{}`},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.policy+" "+testCase.data1+" "+testCase.data2, func(t *testing.T) {
+			options := newTestOptions("{}", func(options *ComparisonOptions) { options.NullPolicy = testCase.policy })
+
+			if actual := toJson(t, mustCompare(t, testCase.data1, testCase.data2, options)); actual != testCase.expected {
+				t.Errorf("expected %s, got %s", testCase.expected, actual)
+			}
+		})
+	}
+}
+
+func TestNullDiffKind(t *testing.T) {
+	options := newTestOptions("{}", func(options *ComparisonOptions) { options.NullPolicy = nullsSTRICT })
+	comparison := mustCompare(t, `{"a":null,"b":1}`, `{"b":2}`, options)
+
+	kinds := map[string]string{}
+	walkDiffs(comparison, "", func(diffPath, kind string, diff map[string]interface{}) { kinds[diffPath] = kind })
+
+	if kinds[">a"] != diffNULL || kinds[">b"] != diffMODIFIED {
+		t.Errorf("unexpected kinds: %v", kinds)
+	}
+}
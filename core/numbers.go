@@ -0,0 +1,90 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+)
+
+//------------------------------------------------------------------------------
+// Here we handle the numbers exactly, when the JSON files are decoded with the
+// UseNumber option: no more rounding of the big IDs, or of the amounts
+//------------------------------------------------------------------------------
+
+// unmarshalJson decodes a JSON document; its numbers are json.Number if asked to, rather than float64
+func unmarshalJson(data []byte, useNumber bool) (interface{}, error) {
+	var obj interface{}
+
+	if !useNumber {
+		if errUnmarsh := json.Unmarshal(data, &obj); errUnmarsh != nil {
+			return nil, errUnmarsh
+		}
+
+		return obj, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	if errDecode := decoder.Decode(&obj); errDecode != nil {
+		return nil, errDecode
+	}
+
+	// like json.Unmarshal, we do not accept anything after the document
+	if _, errToken := decoder.Token(); !errors.Is(errToken, io.EOF) {
+		return nil, fmt.Errorf("invalid data after the top-level value, at offset %d", decoder.InputOffset())
+	}
+
+	return obj, nil
+}
+
+// getExactNumber returns the exact value of a number, be it a json.Number, or a float64 - taken with its shortest representation, which is
+// what was written in the JSON file, unless it had more digits than a float64 can hold
+func getExactNumber(value interface{}) (*big.Rat, bool) {
+	switch typedValue := value.(type) {
+	case json.Number:
+		return new(big.Rat).SetString(typedValue.String())
+	case float64:
+		return new(big.Rat).SetString(strconv.FormatFloat(typedValue, 'g', -1, 64))
+	}
+
+	return nil, false
+}
+
+// sameNumbers tells if 2 numbers have the same value, e.g. 1.50 and 1.5
+func sameNumbers(number1, number2 json.Number) bool {
+	exact1, ok1 := getExactNumber(number1)
+	exact2, ok2 := getExactNumber(number2)
+
+	if !ok1 || !ok2 {
+		return number1 == number2
+	}
+
+	return exact1.Cmp(exact2) == 0
+}
+
+// formatExactNumber writes a number in a canonical way, to build keys with it: "1.50", "1.5" and "15e-1" are all "1.5"
+func formatExactNumber(number json.Number) string {
+	exact, ok := getExactNumber(number)
+	if !ok {
+		return number.String()
+	}
+
+	if exact.IsInt() {
+		return exact.Num().String()
+	}
+
+	// a decimal number has a denominator dividing a power of 10, which gives us the number of decimals needed
+	nbDecimals, power := 0, big.NewInt(1)
+
+	for new(big.Int).Mod(power, exact.Denom()).Sign() != 0 {
+		power.Mul(power, big.NewInt(10)) //nolint:gomnd
+		nbDecimals++
+	}
+
+	return exact.FloatString(nbDecimals)
+}
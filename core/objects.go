@@ -1,6 +1,7 @@
 package core
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"runtime/debug"
@@ -42,6 +43,21 @@ func compareObjects(root1, root2 *JsonEntity, idParam *IdentificationParameter,
 		return nodif(), nil
 	}
 
+	// the library users may have their own way of comparing the values here
+	if comparison, compared := options.compareCustom(idParam, obj1, obj2, currentPathValue); compared {
+		return comparison, nil
+	}
+
+	// or a built-in one may have been selected in the ID params
+	if comparison, compared := idParam.compareBuiltin(obj1, obj2); compared {
+		return comparison, nil
+	}
+
+	// the strings can embed some JSON or XML payloads, to compare as data trees
+	if comparison, compared, errComp := compareEmbedded(idParam, obj1, obj2, options, currentPathValue); compared {
+		return comparison, errComp
+	}
+
 	// if the kinds are not equal, then we signal an error
 	if obj1Kind != obj2Kind {
 		// Go's unmarshalling process can lead to having different kinds here, when we juste have kind1 = sliceOf(kind2) or kind2 = sliceOf(kind1);
@@ -49,9 +65,22 @@ func compareObjects(root1, root2 *JsonEntity, idParam *IdentificationParameter,
 		if obj1Kind == reflect.Slice { // here, we assume that obj1 is a slice of objects of the same kind as the single object obj2; but this could fail!
 			switch obj2Kind {
 			case reflect.String:
-				return compareSlicesOfStrings(idParam, obj1.([]string), []string{obj2.(string)}, options, currentPathValue)
+				// the slices coming from an XML file may be slices of strings, or of elements having attributes or children; but the exact
+				// numbers of the JSON files are strings too
+				if str2, isString := obj2.(string); isString {
+					if _, isGeneric := obj1.([]interface{}); !isGeneric {
+						return compareObjects(root1, root2, idParam, obj1, []string{str2}, options, currentPathValue)
+					}
+				}
+
+				return compareSlicesOfObjects(root1, root2, idParam, obj1.([]interface{}), []interface{}{obj2}, options, currentPathValue)
 			case reflect.Map:
-				return compareSlicesOfMaps(root1, root2, idParam, obj1.([]map[string]interface{}), []map[string]interface{}{obj2.(map[string]interface{})}, options, currentPathValue)
+				// the slice may not come from an XML file
+				if slice1, isMaps := obj1.([]map[string]interface{}); isMaps {
+					return compareSlicesOfMaps(root1, root2, idParam, slice1, []map[string]interface{}{obj2.(map[string]interface{})}, options, currentPathValue)
+				}
+
+				return compareSlicesOfObjects(root1, root2, idParam, obj1.([]interface{}), []interface{}{obj2}, options, currentPathValue)
 			default:
 				return compareSlicesOfObjects(root1, root2, idParam, obj1.([]interface{}), []interface{}{obj2}, options, currentPathValue)
 			}
@@ -60,9 +89,22 @@ func compareObjects(root1, root2 *JsonEntity, idParam *IdentificationParameter,
 		if obj2Kind == reflect.Slice { // here, we assume that obj2 is a slice of objects of the same kind as the single object obj1; but this could fail!
 			switch obj1Kind {
 			case reflect.String:
-				return compareSlicesOfStrings(idParam, []string{obj1.(string)}, obj2.([]string), options, currentPathValue)
+				// the slices coming from an XML file may be slices of strings, or of elements having attributes or children; but the exact
+				// numbers of the JSON files are strings too
+				if str1, isString := obj1.(string); isString {
+					if _, isGeneric := obj2.([]interface{}); !isGeneric {
+						return compareObjects(root1, root2, idParam, []string{str1}, obj2, options, currentPathValue)
+					}
+				}
+
+				return compareSlicesOfObjects(root1, root2, idParam, []interface{}{obj1}, obj2.([]interface{}), options, currentPathValue)
 			case reflect.Map:
-				return compareSlicesOfMaps(root1, root2, idParam, []map[string]interface{}{obj1.(map[string]interface{})}, obj2.([]map[string]interface{}), options, currentPathValue)
+				// the slice may not come from an XML file
+				if slice2, isMaps := obj2.([]map[string]interface{}); isMaps {
+					return compareSlicesOfMaps(root1, root2, idParam, []map[string]interface{}{obj1.(map[string]interface{})}, slice2, options, currentPathValue)
+				}
+
+				return compareSlicesOfObjects(root1, root2, idParam, []interface{}{obj1}, obj2.([]interface{}), options, currentPathValue)
 			default:
 				return compareSlicesOfObjects(root1, root2, idParam, []interface{}{obj1}, obj2.([]interface{}), options, currentPathValue)
 			}
@@ -86,23 +128,51 @@ func compareObjects(root1, root2 *JsonEntity, idParam *IdentificationParameter,
 		}
 
 	case reflect.String:
-		if obj1.(string) != obj2.(string) {
+		// with the UseNumber option, the numbers are json.Number, i.e. strings, to compare as numbers
+		number1, isNumber1 := obj1.(json.Number)
+		number2, isNumber2 := obj2.(json.Number)
+
+		switch {
+		case isNumber1 && isNumber2:
+			if !sameNumbers(number1, number2) {
+				return one_two(obj1, obj2), nil
+			}
+		case isNumber1 || isNumber2:
+			return one_two(obj1, obj2), nil
+		case obj1.(string) != obj2.(string):
 			return one_two(obj1, obj2), nil
 		}
 
 	case reflect.Slice:
+		// the repeated XML elements having just some text are slices of strings, but they may have attributes or children in the other file
+		obj1, obj2 = alignSlicesOfElements(obj1, obj2, options.getXmlTextKey())
+
 		switch obj1.(type) {
 		case []interface{}:
-			return compareSlicesOfObjects(root1, root2, idParam, obj1.([]interface{}), obj2.([]interface{}), options, currentPathValue)
+			if slice2, isSame := obj2.([]interface{}); isSame {
+				return compareSlicesOfObjects(root1, root2, idParam, obj1.([]interface{}), slice2, options, currentPathValue)
+			}
 
 		case []map[string]interface{}:
-			if idParam == nil {
-				panic(fmt.Errorf("No id param at path '%s'. Currently compared slices of maps: \n\nslice 1:%v\n\nslice 2:%v", currentPathValue, obj1, obj2))
+			if slice2, isSame := obj2.([]map[string]interface{}); isSame {
+				if idParam == nil {
+					return nil, fmt.Errorf("No id param at path '%s'. Currently compared slices of maps: \n\nslice 1:%v\n\nslice 2:%v", currentPathValue, obj1, obj2)
+				}
+
+				return compareSlicesOfMaps(root1, root2, idParam, obj1.([]map[string]interface{}), slice2, options, currentPathValue)
 			}
 
-			return compareSlicesOfMaps(root1, root2, idParam, obj1.([]map[string]interface{}), obj2.([]map[string]interface{}), options, currentPathValue)
+		case []string:
+			// e.g. the repeated XML elements having just some text
+			if slice2, isStrings := obj2.([]string); isStrings {
+				return compareSlicesOfStrings(idParam, obj1.([]string), slice2, options, currentPathValue)
+			}
 		}
 
+		// we cannot compare slices of different types
+		return nil, fmt.Errorf("Issue at path '%s' (%s): slice of type '%T' in the first file VS slice of type '%T' in the second file",
+			idParam.toString(), currentPathValue, obj1, obj2)
+
 	case reflect.Map:
 		return compareJsonEntities(idParam, entityFrom(obj1, root1), entityFrom(obj2, root2), options, currentPathValue, false)
 
@@ -0,0 +1,66 @@
+package core
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// newTestOptions builds some resolved options, with the given ID params, and the given settings applied
+func newTestOptions(idParams string, settings func(options *ComparisonOptions)) *ComparisonOptions {
+	options := &ComparisonOptions{IdParamsString: idParams, Silent: true}
+	if settings != nil {
+		settings(options)
+	}
+
+	options.SetDefaultLogger().Resolve()
+
+	return options
+}
+
+// mustCompare compares 2 JSON or XML payloads, failing the test on any error
+func mustCompare(t *testing.T, data1, data2 string, options *ComparisonOptions) Comparison {
+	t.Helper()
+
+	comparison, errComp := compareBytes([]byte(data1), []byte(data2), options, false)
+	if errComp != nil {
+		t.Fatalf("unexpected error: %s", errComp)
+	}
+
+	return comparison
+}
+
+// toJson serializes a comparison, so that it can be checked against what's expected
+func toJson(t *testing.T, comparison Comparison) string {
+	t.Helper()
+
+	data, errMarshal := json.Marshal(comparison)
+	if errMarshal != nil {
+		t.Fatalf("cannot marshal the comparison: %s", errMarshal)
+	}
+
+	return string(data)
+}
+
+func TestCompareSliceWithScalar(t *testing.T) {
+	cases := []struct {
+		name         string
+		data1, data2 string
+		useNumber    bool
+		expected     string
+	}{
+		{"exact number VS slice", `{"a":[1,2]}`, `{"a":1}`, true, `{"a":{"2":{"_del_":2}}}`},
+		{"slice VS exact number", `{"a":1}`, `{"a":[1,2]}`, true, `{"a":{"2":{"_new_":2}}}`},
+		{"string VS slice", `{"a":"x"}`, `{"a":["x","y"]}`, false, `{"a":{"y":{"_new_":"y"}}}`},
+		{"same exact numbers", `{"a":[1]}`, `{"a":1.0}`, true, `{}`},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			options := newTestOptions("{}", func(options *ComparisonOptions) { options.UseNumber = testCase.useNumber })
+
+			if actual := toJson(t, mustCompare(t, testCase.data1, testCase.data2, options)); actual != testCase.expected {
+				t.Errorf("expected %s, got %s", testCase.expected, actual)
+			}
+		})
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path"
 	"strings"
+	"sync"
 )
 
 //------------------------------------------------------------------------------
@@ -12,19 +14,50 @@ import (
 //------------------------------------------------------------------------------
 
 type ComparisonOptions struct {
-	FileType       FileType                 // the type of the files we're comparing
-	IdParamsString string                   // a JSON representation of a IdentificationParameter parameter; can be the path to an existing JSON file
-	IdParams       *IdentificationParameter // the properties (values of the map) serving as unique IDs for given paths (keys of the map)
-	Check          bool                     // if true, then the ID params are output to allow for some checks
-	Fast           bool                     // if true, then, in an array, an object's index is used as its IDProp, if none is specified for its path in the data tree; i.e. the IDProp `#index` is used, instead of nothing
-	Silent         bool                     // if true, then no info / warning message is written out
-	StopAtFirst    bool                     // if true, then, when comparing folders, we stop at the first couple of files that differ
-	Logger         Logger                   // a logger
-	IgnoredString  string                   // the files to ignore, separated by a comma
-	Ignored        map[string]bool          // the ignored files
-	AllowRaw       bool                     // if true, then it's allowed to display the raw JSON entities as difference, when added or removed; else, a display template is required
-	IsXml          bool                     // if true, then the compared files are XML files
-	NParallel      int                      // the number of routines used at the same time when comparing several files at once (i.e. comparing folders)
+	FileType            FileType                 // the type of the files we're comparing
+	IdParamsString      string                   // a JSON representation of a IdentificationParameter parameter; can be the path to an existing JSON file
+	IdParams            *IdentificationParameter // the properties (values of the map) serving as unique IDs for given paths (keys of the map)
+	Check               bool                     // if true, then the ID params are output to allow for some checks
+	Fast                bool                     // if true, then, in an array, an object's index is used as its IDProp, if none is specified for its path in the data tree; i.e. the IDProp `#index` is used, instead of nothing
+	Silent              bool                     // if true, then no info / warning message is written out
+	StopAtFirst         bool                     // if true, then, when comparing folders, we stop at the first couple of files that differ
+	Logger              Logger                   // a logger
+	IgnoredString       string                   // the files or folders to ignore, by name or relative path, separated by a comma
+	Ignored             map[string]bool          // the ignored files
+	AllowRaw            bool                     // if true, then it's allowed to display the raw JSON entities as difference, when added or removed; else, a display template is required
+	IsXml               bool                     // if true, then the compared files are XML files
+	NParallel           int                      // the number of routines used at the same time when comparing several files at once (i.e. comparing folders)
+	PairCompressed      bool                     // if true, then, when comparing folders, a compressed file can be paired with its uncompressed version, e.g. "foo.json.gz" with "foo.json"
+	PairingString       string                   // a JSON representation of a PairingParameter; can be the path to an existing JSON file
+	Pairing             *PairingParameter        // how to pair the files of 2 folders, when they don't have the same names
+	SummaryTop          int                      // if > 0, then, when comparing folders, an aggregated report of the differences is produced, with this number of top offending paths
+	BaselineFile        string                   // the path to a baseline file, listing known differences that should not be reported
+	Baseline            *Baseline                // the known, accepted differences
+	XmlnsString         string                   // the canonical prefixes for the namespace URIs, as "prefix=uri" separated by commas; can be the path to a file listing them
+	Xmlns               map[string]string        // the canonical prefixes, by namespace URI
+	XmlSpace            string                   // how to handle the whitespace in the XML text nodes: "trim" (by default), "collapse" or "preserve"
+	XmlComments         bool                     // if true, then the XML comments are compared too, as "#comment"
+	XmlPIs              bool                     // if true, then the XML processing instructions are compared too, as "#pi"
+	XmlMixed            bool                     // if true, then the XML text mixed with child elements is kept, in order, as "#mixed"
+	XmlAttrPrefix       string                   // the prefix of the XML attributes' names: "@" by default
+	XmlTextKey          string                   // the key of the text of the XML elements having attributes or children: "#text" by default
+	XmlAttrsAsChildren  bool                     // if true, then the XML attributes are decoded like child elements, without prefix, so that <a id="1"/> equals <a><id>1</id></a>
+	RepeatableString    string                   // the path to an XSD, or a comma-separated list of XML element paths (e.g. "order.line") - or a file listing them - to always decode as arrays
+	Repeatable          RepeatableElements       // the XML elements that are always decoded as arrays
+	NormalizationString string                   // how to normalize all the strings before comparing them, as a JSON, or the path to a JSON file, e.g. {"whitespace": "trim", "case": "fold"}
+	Normalization       *StringNormalization     // how to normalize all the strings, unless specified otherwise in the ID params
+	Embedded            bool                     // if true, then the strings holding JSON or XML documents - possibly encoded in base64 - are decoded, and compared as data trees
+	UseNumber           bool                     // if true, then the numbers of the JSON files are decoded exactly, rather than as float64 - e.g. the IDs above 2^53, or precise amounts
+	NullPolicy          string                   // how to handle the null, empty or missing values: "strict", "null-equals-missing" (by default) or "empty-equals-missing"
+	Lenient             bool                     // if true, then the values are compared leniently: numeric strings as numbers, "true" / "false" as booleans, and "" as null
+	Explain             string                   // the path of an ID param (e.g. "data.vehicule"), or "*" for all, for which we explain how the keys of the array elements are built
+
+	// technical properties
+	explanations       *keyTrace // the traces of the keys built, as children of this node
+	explainMx          *sync.Mutex
+	implicitLenient    bool                  // true if the values are compared leniently only because an XML file is compared with a JSON file
+	comparators        map[string]Comparator // the custom comparators, by ID param path
+	comparatorPatterns []*comparatorPattern  // the custom comparators, for the paths matching some patterns
 }
 
 func (thisComp *ComparisonOptions) GetFileType() FileType {
@@ -48,6 +81,20 @@ func (thisComp *ComparisonOptions) GetIdParams() *IdentificationParameter {
 func (thisComp *ComparisonOptions) Resolve() {
 	thisComp.IdParams = thisComp.getIdParamsFromString()
 	thisComp.Ignored = thisComp.getIgnoredFiles()
+	thisComp.Pairing = thisComp.getPairingFromString()
+	thisComp.Baseline = thisComp.getBaselineFromFile()
+	thisComp.Repeatable = thisComp.getRepeatableFromString()
+	thisComp.Xmlns = thisComp.getXmlnsFromString()
+	thisComp.IdParams.xmlTextKey = thisComp.getXmlTextKey()
+	checkXmlSpace(thisComp.XmlSpace)
+	checkNullPolicy(thisComp.NullPolicy)
+	thisComp.Normalization = thisComp.getNormalizationFromString()
+
+	if errNormalization := thisComp.IdParams.resolveNormalization(thisComp.Normalization); errNormalization != nil {
+		panic(fmt.Errorf("not a valid ID parameter: %s", errNormalization))
+	}
+
+	thisComp.explanations, thisComp.explainMx = &keyTrace{}, new(sync.Mutex)
 	thisComp.FileType = FileTypeJSON
 
 	if thisComp.IsXml {
@@ -65,27 +112,37 @@ func (thisComp *ComparisonOptions) getIgnoredFiles() map[string]bool {
 	return result
 }
 
+// isIgnored tells if the file with the given relative path, e.g. "sub/foo.json", is ignored: by its name or path, or by the name or path of
+// one of its parent folders
+func (thisComp *ComparisonOptions) isIgnored(filename string) bool {
+	for current := filename; current != "." && current != "/" && current != ""; current = path.Dir(current) {
+		if thisComp.Ignored[path.Base(current)] || thisComp.Ignored[current] {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (thisComp *ComparisonOptions) getIdParamsFromString() *IdentificationParameter {
 	if thisComp.IdParamsString == "" {
 		panic("no ID params!")
 	}
 
-	// at first, we suppose the whole JSON string has been provided
-	idParamsJsonString := thisComp.IdParamsString
-
-	// but what if it's the path to an existing file ?
-	if _, errExist := os.Stat(thisComp.IdParamsString); errExist == nil {
-		fileBytes, errRead := os.ReadFile(thisComp.IdParamsString)
-		if errRead != nil {
-			panic(fmt.Sprintf("error while readling config file (%s). Cause: %s", thisComp.IdParamsString, errRead))
-		}
+	// the ID params can be written in JSON, JSONC or YAML, and split into several files
+	idParamsJson, errConfig := getConfigJson(thisComp.IdParamsString)
+	if errConfig != nil {
+		panic(fmt.Errorf("not a valid ID parameter: %s", errConfig))
+	}
 
-		idParamsJsonString = string(fileBytes)
+	// checking the ID params against their schema, to report all the errors at once, with their location
+	if errs := validateIdParams(idParamsJson); len(errs) > 0 {
+		panic(fmt.Errorf("not a valid ID parameter:\n%s", strings.Join(errs, "\n")))
 	}
 
 	param := &IdentificationParameter{}
 
-	if err := json.Unmarshal([]byte(idParamsJsonString), param); err != nil {
+	if err := json.Unmarshal(idParamsJson, param); err != nil {
 		panic(fmt.Errorf("not a valid JSON (%s)", err))
 	}
 
@@ -95,3 +152,49 @@ func (thisComp *ComparisonOptions) getIdParamsFromString() *IdentificationParame
 
 	return param
 }
+
+func (thisComp *ComparisonOptions) getPairingFromString() *PairingParameter {
+	if thisComp.PairingString == "" {
+		return nil
+	}
+
+	pairing := &PairingParameter{}
+
+	if err := json.Unmarshal([]byte(getStringOrFileContent(thisComp.PairingString)), pairing); err != nil {
+		panic(fmt.Errorf("not a valid JSON for the pairing (%s)", err))
+	}
+
+	if err := pairing.Resolve(); err != nil {
+		panic(fmt.Errorf("not a valid pairing parameter: %s", err))
+	}
+
+	return pairing
+}
+
+// getStringOrFileContent returns the given string, or - if it's the path to an existing file - this file's content
+func getStringOrFileContent(str string) string {
+	// at first, we suppose the whole string has been provided, but what if it's the path to an existing file ?
+	if _, errExist := os.Stat(str); errExist == nil {
+		fileBytes, errRead := os.ReadFile(str)
+		if errRead != nil {
+			panic(fmt.Sprintf("error while readling config file (%s). Cause: %s", str, errRead))
+		}
+
+		return string(fileBytes)
+	}
+
+	return str
+}
+
+func (thisComp *ComparisonOptions) getBaselineFromFile() *Baseline {
+	if thisComp.BaselineFile == "" {
+		return nil
+	}
+
+	baseline, errRead := ReadBaseline(thisComp.BaselineFile)
+	if errRead != nil {
+		panic(errRead)
+	}
+
+	return baseline
+}
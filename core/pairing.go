@@ -0,0 +1,234 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+//------------------------------------------------------------------------------
+// Here we configure how to pair the files of 2 folders, when they don't have
+// the same names
+//------------------------------------------------------------------------------
+
+// PairingParameter allows to pair files with different names, when comparing folders
+type PairingParameter struct {
+	Renames    map[string]string `json:"renames,omitempty"`    // explicit pairs: the name of a file in the first folder -> the name of its counterpart in the second folder
+	Keys       []string          `json:"keys,omitempty"`       // regular expressions whose capture groups are extracted from the file names; files with the same extracted key are paired
+	Similarity float64           `json:"similarity,omitempty"` // if > 0, the files left without a pair are paired by content similarity, when it's at least this value (between 0 and 1)
+
+	// technical properties
+	keysRegexps []*regexp.Regexp
+}
+
+// Resolve checks this pairing parameter, and prepares its regular expressions
+func (thisPairing *PairingParameter) Resolve() error {
+	for _, key := range thisPairing.Keys {
+		keyRegexp, errCompile := regexp.Compile(key)
+		if errCompile != nil {
+			return fmt.Errorf("invalid key regular expression '%s': %s", key, errCompile)
+		}
+
+		thisPairing.keysRegexps = append(thisPairing.keysRegexps, keyRegexp)
+	}
+
+	if thisPairing.Similarity < 0 || thisPairing.Similarity > 1 {
+		return fmt.Errorf("the similarity must be between 0 and 1, not %f", thisPairing.Similarity)
+	}
+
+	return nil
+}
+
+// getKey returns the key extracted from the given file name, by the first regular expression matching it - or the name itself
+func (thisPairing *PairingParameter) getKey(filename string) string {
+	if thisPairing == nil {
+		return filename
+	}
+
+	for _, keyRegexp := range thisPairing.keysRegexps {
+		if matches := keyRegexp.FindStringSubmatch(filename); matches != nil {
+			// no capture group ? then the whole match is the key
+			if len(matches) == 1 {
+				return matches[0]
+			}
+
+			return strings.Join(matches[1:], sepPIPE)
+		}
+	}
+
+	return filename
+}
+
+// getPairingKey returns the key used to pair a file of one source with a file of the other source
+func (thisComp *ComparisonOptions) getPairingKey(filename string) string {
+	if thisComp.PairCompressed {
+		filename = uncompressedName(filename)
+	}
+
+	return thisComp.Pairing.getKey(filename)
+}
+
+// getRenames returns the explicit renames, possibly completed by the given ones - the explicit ones taking precedence
+func (thisComp *ComparisonOptions) getRenames(renames map[string]string) map[string]string {
+	if thisComp.Pairing == nil || len(thisComp.Pairing.Renames) == 0 {
+		return renames
+	}
+
+	allRenames := map[string]string{}
+
+	for nameOne, nameTwo := range renames {
+		allRenames[nameOne] = nameTwo
+	}
+
+	for nameOne, nameTwo := range thisComp.Pairing.Renames {
+		allRenames[nameOne] = nameTwo
+	}
+
+	return allRenames
+}
+
+//------------------------------------------------------------------------------
+// Pairing the leftovers by content similarity
+//------------------------------------------------------------------------------
+
+// a possible pairing between 2 files
+type similarityCandidate struct {
+	indexOne   int     // the index of the "only one" pair
+	indexTwo   int     // the index of the "only two" pair
+	similarity float64 // how similar the 2 files are
+}
+
+// the tokens used to evaluate the similarity between 2 contents
+var tokenRegexp = regexp.MustCompile(`[\p{L}\p{N}_.:@#-]+`)
+
+// pairBySimilarity pairs the files that only exist on one side, with the most similar files that only exist on the other side,
+// provided their similarity is high enough
+//nolint:gocognit
+func pairBySimilarity(pairs []*filePair, sourceOne, sourceTwo fileSource, options *ComparisonOptions) ([]*filePair, error) {
+	// the files that have not found their pair
+	var onlyOne, onlyTwo []int
+
+	for index, pair := range pairs {
+		if pair.nameTwo == "" {
+			onlyOne = append(onlyOne, index)
+		} else if pair.nameOne == "" {
+			onlyTwo = append(onlyTwo, index)
+		}
+	}
+
+	if len(onlyOne) == 0 || len(onlyTwo) == 0 {
+		return pairs, nil
+	}
+
+	// reading the tokens of every file left
+	tokensOne, errOne := getFilesTokens(sourceOne, pairs, onlyOne, true)
+	if errOne != nil {
+		return nil, errOne
+	}
+
+	tokensTwo, errTwo := getFilesTokens(sourceTwo, pairs, onlyTwo, false)
+	if errTwo != nil {
+		return nil, errTwo
+	}
+
+	// evaluating all the possible pairings
+	candidates := []*similarityCandidate{}
+
+	for i, indexOne := range onlyOne {
+		for j, indexTwo := range onlyTwo {
+			if similarity := getSimilarity(tokensOne[i], tokensTwo[j]); similarity >= options.Pairing.Similarity {
+				candidates = append(candidates, &similarityCandidate{indexOne: indexOne, indexTwo: indexTwo, similarity: similarity})
+			}
+		}
+	}
+
+	// the most similar files are paired first
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].similarity > candidates[j].similarity
+	})
+
+	used := map[int]bool{}
+
+	for _, candidate := range candidates {
+		if used[candidate.indexOne] || used[candidate.indexTwo] {
+			continue
+		}
+
+		used[candidate.indexOne] = true
+		used[candidate.indexTwo] = true
+
+		pairs[candidate.indexOne].nameTwo = pairs[candidate.indexTwo].nameTwo
+
+		if !options.Silent {
+			options.Logger.Info("Paired file '%s' with file '%s' (similarity: %.2f)",
+				pairs[candidate.indexOne].nameOne, pairs[candidate.indexOne].nameTwo, candidate.similarity)
+		}
+	}
+
+	// removing the pairs that have been merged into others
+	result := []*filePair{}
+
+	for index, pair := range pairs {
+		if pair.nameOne != "" || !used[index] {
+			result = append(result, pair)
+		}
+	}
+
+	return result, nil
+}
+
+// getFilesTokens reads the given files, and counts the tokens found in each of them
+func getFilesTokens(source fileSource, pairs []*filePair, indexes []int, first bool) ([]map[string]int, error) {
+	allTokens := make([]map[string]int, len(indexes))
+
+	for i, index := range indexes {
+		name := pairs[index].nameTwo
+		if first {
+			name = pairs[index].nameOne
+		}
+
+		fileBytes, errRead := readSourceFile(source, name)
+		if errRead != nil {
+			return nil, errRead
+		}
+
+		tokens := map[string]int{}
+		for _, token := range tokenRegexp.FindAll(fileBytes, -1) {
+			tokens[string(token)]++
+		}
+
+		allTokens[i] = tokens
+	}
+
+	return allTokens, nil
+}
+
+// getSimilarity computes the (multiset) Jaccard index between 2 sets of tokens
+func getSimilarity(tokens1, tokens2 map[string]int) float64 {
+	intersection, union := 0, 0
+
+	for token, count1 := range tokens1 {
+		count2 := tokens2[token]
+
+		if count1 < count2 {
+			intersection += count1
+			union += count2
+		} else {
+			intersection += count2
+			union += count1
+		}
+	}
+
+	for token, count2 := range tokens2 {
+		if _, inOne := tokens1[token]; !inOne {
+			union += count2
+		}
+	}
+
+	if union == 0 {
+		return 1
+	}
+
+	return float64(intersection) / float64(union)
+}
@@ -11,17 +11,27 @@ import (
 
 // IdentificationParameter allows to recursively describe how to identity the entities within arrays in a data tree
 type IdentificationParameter struct {
-	At       string                              `json:"at,omitempty"`   // the relative path at which to use this identification parameter
-	Use      []string                            `json:"_use,omitempty"` // which simple properties to concatenate to form a key
-	Tpl1     []string                            `json:"tpl1,omitempty"` // a formattable string (Go template) to build an alias for an object, instead of outputting it completely in the comparison
-	TplN     []string                            `json:"tplN,omitempty"` // a formattable string (Go template) to build an alias for an object, instead of outputting it completely in the comparison
-	Incr     bool                                `json:"incr,omitempty"` // if true, then any key built with this ID param is augmented with a counter of its occurrences
-	When     []*ConditionalIDParameter           `json:"when,omitempty"` // when to apply this identification parameter, and what to do (_use, look, or when ?)
-	Look     []*IdentificationParameter          `json:"look,omitempty"` // which relationships to look into
-	For      map[string]*IdentificationParameter `json:"_for,omitempty"` // how to deal with the embedded objects from this place
-	Name     string                              `json:"name,omitempty"` // a name for this ID parameter, that may be used as a prefix for the keys built here
-	FullPath string                              `json:"path,omitempty"` // the relative path at which to use this identification parameter
-	Keep     bool                                `json:"keep,omitempty"` // if true, then, when comparing slice elements with this ID param, we're not clearing the identical elements before comparing the diverging ones
+	At        string                              `json:"at,omitempty"`        // the relative path at which to use this identification parameter
+	Use       []string                            `json:"_use,omitempty"`      // which simple properties to concatenate to form a key
+	Tpl1      []string                            `json:"tpl1,omitempty"`      // a formattable string (Go template) to build an alias for an object, instead of outputting it completely in the comparison
+	TplN      []string                            `json:"tplN,omitempty"`      // a formattable string (Go template) to build an alias for an object, instead of outputting it completely in the comparison
+	Incr      bool                                `json:"incr,omitempty"`      // if true, then any key built with this ID param is augmented with a counter of its occurrences
+	When      []*ConditionalIDParameter           `json:"when,omitempty"`      // when to apply this identification parameter, and what to do (_use, look, or when ?)
+	Look      []*IdentificationParameter          `json:"look,omitempty"`      // which relationships to look into
+	For       map[string]*IdentificationParameter `json:"_for,omitempty"`      // how to deal with the embedded objects from this place
+	Name      string                              `json:"name,omitempty"`      // a name for this ID parameter, that may be used as a prefix for the keys built here
+	FullPath  string                              `json:"path,omitempty"`      // the relative path at which to use this identification parameter
+	Keep      bool                                `json:"keep,omitempty"`      // if true, then, when comparing slice elements with this ID param, we're not clearing the identical elements before comparing the diverging ones
+	Match     string                              `json:"match,omitempty"`     // if "similarity", then the array elements are not keyed, but paired by similarity across the 2 arrays
+	Lenient   *bool                               `json:"lenient,omitempty"`   // if true, then the values at this path - and below, unless specified otherwise - are compared leniently, e.g. "12.50" = 12.5
+	Moves     bool                                `json:"moves,omitempty"`     // if true, then the elements of the arrays at this path that have changed position are reported, with their positions; implies "keep"
+	MaxDist   float64                             `json:"maxDist,omitempty"`   // with "match": "similarity", the maximum distance (between 0 and 1; 0.5 by default) for 2 elements to be paired
+	Xml       *XmlNormalization                   `json:"xml,omitempty"`       // how to normalize the XML content at this path - and below, unless specified otherwise
+	As        string                              `json:"as,omitempty"`        // how to compare the simple values at this path: as a "datetime", "decimal", "uuid" or "duration"
+	Layouts   []string                            `json:"layouts,omitempty"`   // with "as": "datetime", the layouts to parse the datetimes with, in Go's format, e.g. "02/01/2006 15:04"
+	Normalize *StringNormalization                `json:"normalize,omitempty"` // how to normalize the strings at this path - and below - before comparing them, or building keys with them
+	Embedded  string                              `json:"embedded,omitempty"`  // if "json", "xml", "base64" or "auto", then the strings at this path are decoded, and compared as data trees, with this ID param as root
+	Nulls     string                              `json:"nulls,omitempty"`     // how to handle the null, empty or missing values at this path - and below, unless specified otherwise: "strict", "null-equals-missing" or "empty-equals-missing"
 
 	// technical properties
 	parent             *IdentificationParameter
@@ -30,6 +40,8 @@ type IdentificationParameter struct {
 	buildTplN          *template.Template
 	withinWhen         bool
 	withinWhenResolved bool
+	xmlTextKey         string               // the key of the XML text, known by the root ID param
+	normalization      *StringNormalization // the normalization of the strings at this path, inherited from above if need be
 }
 
 // ConditionalIDParameter is an IdentificationParameter that applies only if a given prop has the designated value
@@ -58,6 +70,11 @@ func (thisParam *IdentificationParameter) buildFullPath() string {
 
 // String returns this ID param's full path, building it once
 func (thisParam *IdentificationParameter) toString() string {
+	// the undeclared paths have no ID param
+	if thisParam == nil {
+		return ""
+	}
+
 	if thisParam.FullPath == "" {
 		thisParam.FullPath = thisParam.buildFullPath()
 	}
@@ -134,6 +151,15 @@ func (thisParam *IdentificationParameter) doResolve(full bool) error {
 	return thisParam.checkValidity()
 }
 
+// identifiesElements tells if this ID param is meant to build keys for the elements of an array
+func (thisParam *IdentificationParameter) identifiesElements() bool {
+	if thisParam == nil {
+		return false
+	}
+
+	return len(thisParam.Use) > 0 || len(thisParam.Look) > 0 || len(thisParam.When) > 0 || thisParam.Incr
+}
+
 // isWithinWhen tells if an identification parameter is somehow embedded in a "When" ID param
 func (thisParam *IdentificationParameter) isWithinWhen() bool {
 	if thisParam == nil {
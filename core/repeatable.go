@@ -0,0 +1,240 @@
+package core
+
+import (
+	"encoding/xml"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+//------------------------------------------------------------------------------
+// Here we determine which XML elements are repeatable, from an XSD or a list
+// of paths, so that they are always decoded as arrays - even when single
+//------------------------------------------------------------------------------
+
+// RepeatableElements : the paths of the repeatable XML elements, e.g. "order.line"
+type RepeatableElements map[string]bool
+
+// getRepeatableFromString reads the repeatable elements from an XSD file, from a file listing their paths, or from a comma-separated list of paths
+func (thisComp *ComparisonOptions) getRepeatableFromString() RepeatableElements {
+	if thisComp.RepeatableString == "" {
+		return nil
+	}
+
+	content := getStringOrFileContent(thisComp.RepeatableString)
+
+	// an XSD file ?
+	if strings.EqualFold(filepath.Ext(thisComp.RepeatableString), ".xsd") || strings.HasPrefix(strings.TrimSpace(content), "<") {
+		repeatable, errXsd := ReadRepeatableFromXsd([]byte(content))
+		if errXsd != nil {
+			panic(fmt.Errorf("not a valid XSD for the repeatable elements (%s)", errXsd))
+		}
+
+		return repeatable
+	}
+
+	// a list of paths
+	repeatable := RepeatableElements{}
+
+	for _, elementPath := range strings.FieldsFunc(content, func(r rune) bool { return r == ',' || r == '\n' || r == '\r' }) {
+		if elementPath = strings.TrimSpace(elementPath); elementPath != "" {
+			repeatable[elementPath] = true
+		}
+	}
+
+	return repeatable
+}
+
+// apply makes sure the repeatable elements of a document, as decoded by decodeXml, are arrays; the attributes have the given prefix
+func (thisRepeatable RepeatableElements) apply(value interface{}, currentPath string, attrPrefix string) interface{} {
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		for key, child := range typedValue {
+			// the attributes cannot be repeated, nor the text, comments, etc.
+			if (attrPrefix != "" && strings.HasPrefix(key, attrPrefix)) || strings.HasPrefix(key, "#") {
+				continue
+			}
+
+			// the namespaces are not part of the paths
+			childPath := strings.TrimPrefix(currentPath+"."+getLocalName(key), ".")
+			child = thisRepeatable.apply(child, childPath, attrPrefix)
+
+			if thisRepeatable[childPath] {
+				switch typedChild := child.(type) {
+				case map[string]interface{}:
+					child = []map[string]interface{}{typedChild}
+				case string:
+					child = []string{typedChild}
+				}
+			}
+
+			typedValue[key] = child
+		}
+
+	case []map[string]interface{}:
+		for _, element := range typedValue {
+			thisRepeatable.apply(element, currentPath, attrPrefix)
+		}
+	}
+
+	return value
+}
+
+//------------------------------------------------------------------------------
+// Reading an XSD
+//------------------------------------------------------------------------------
+
+// xsdNode : any node of an XSD
+type xsdNode struct {
+	XMLName  xml.Name
+	Attrs    []xml.Attr `xml:",any,attr"`
+	Children []*xsdNode `xml:",any"`
+}
+
+func (thisNode *xsdNode) attr(name string) string {
+	for _, attr := range thisNode.Attrs {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+
+	return ""
+}
+
+// isRepeatable tells if this node's "maxOccurs" allows for more than 1 occurrence
+func (thisNode *xsdNode) isRepeatable() bool {
+	maxOccurs := thisNode.attr("maxOccurs")
+	if maxOccurs == "unbounded" {
+		return true
+	}
+
+	nbMax, errConv := strconv.Atoi(maxOccurs)
+
+	return errConv == nil && nbMax > 1
+}
+
+// xsdReader : what's needed to walk through an XSD
+type xsdReader struct {
+	elements     map[string]*xsdNode // the global elements
+	complexTypes map[string]*xsdNode // the named complex types
+	groups       map[string]*xsdNode // the named groups
+	visiting     map[string]bool     // the types being walked through, to handle the recursive types
+	visitingElts map[*xsdNode]bool   // the global elements being walked through, to handle the recursive references
+	repeatable   RepeatableElements
+}
+
+// ReadRepeatableFromXsd returns the paths of the repeatable elements described by the given XSD, starting from its global elements
+func ReadRepeatableFromXsd(xsdBytes []byte) (RepeatableElements, error) {
+	schema := &xsdNode{}
+
+	if errUnmarsh := xml.Unmarshal(xsdBytes, schema); errUnmarsh != nil {
+		return nil, errUnmarsh
+	}
+
+	reader := &xsdReader{
+		elements:     map[string]*xsdNode{},
+		complexTypes: map[string]*xsdNode{},
+		groups:       map[string]*xsdNode{},
+		visiting:     map[string]bool{},
+		visitingElts: map[*xsdNode]bool{},
+		repeatable:   RepeatableElements{},
+	}
+
+	for _, child := range schema.Children {
+		switch child.XMLName.Local {
+		case "element":
+			reader.elements[child.attr("name")] = child
+		case "complexType":
+			reader.complexTypes[child.attr("name")] = child
+		case "group":
+			reader.groups[child.attr("name")] = child
+		}
+	}
+
+	for _, child := range schema.Children {
+		if child.XMLName.Local == "element" {
+			reader.walkElement(child, "", false)
+		}
+	}
+
+	return reader.repeatable, nil
+}
+
+// walkElement handles an element declaration, or reference
+func (thisReader *xsdReader) walkElement(element *xsdNode, currentPath string, repeated bool) {
+	name, content := element.attr("name"), element
+
+	if ref := element.attr("ref"); ref != "" {
+		name = stripXmlPrefix(ref)
+		if content = thisReader.elements[name]; content == nil {
+			return
+		}
+	}
+
+	elementPath := strings.TrimPrefix(currentPath+"."+name, ".")
+
+	if repeated || element.isRepeatable() {
+		thisReader.repeatable[elementPath] = true
+	}
+
+	// an element can reference itself, or one of its ancestors, e.g. a tree of nodes; like the types, it is walked through once at a time
+	if thisReader.visitingElts[content] {
+		return
+	}
+
+	thisReader.visitingElts[content] = true
+
+	// the element's type can be named, or not
+	if typeName := stripXmlPrefix(content.attr("type")); typeName != "" {
+		thisReader.walkType(typeName, elementPath)
+	}
+
+	thisReader.walkContent(content, elementPath, false)
+	delete(thisReader.visitingElts, content)
+}
+
+// walkType handles a named complex type - once at a time, since types can be recursive
+func (thisReader *xsdReader) walkType(typeName, currentPath string) {
+	complexType := thisReader.complexTypes[typeName]
+	if complexType == nil || thisReader.visiting[typeName] {
+		return
+	}
+
+	thisReader.visiting[typeName] = true
+	thisReader.walkContent(complexType, currentPath, false)
+	delete(thisReader.visiting, typeName)
+}
+
+// walkContent handles the content of an element or a type: the children elements may be repeated because of their container
+func (thisReader *xsdReader) walkContent(node *xsdNode, currentPath string, repeated bool) {
+	for _, child := range node.Children {
+		switch child.XMLName.Local {
+		case "element":
+			thisReader.walkElement(child, currentPath, repeated)
+
+		case "complexType", "complexContent":
+			thisReader.walkContent(child, currentPath, repeated)
+
+		case "extension", "restriction":
+			if base := stripXmlPrefix(child.attr("base")); base != "" {
+				thisReader.walkType(base, currentPath)
+			}
+
+			thisReader.walkContent(child, currentPath, repeated)
+
+		case "sequence", "choice", "all":
+			thisReader.walkContent(child, currentPath, repeated || child.isRepeatable())
+
+		case "group":
+			if group := thisReader.groups[stripXmlPrefix(child.attr("ref"))]; group != nil {
+				thisReader.walkContent(group, currentPath, repeated || child.isRepeatable())
+			}
+		}
+	}
+}
+
+// stripXmlPrefix removes the namespace prefix from a qualified name, e.g. "xs:string"
+func stripXmlPrefix(name string) string {
+	return name[strings.LastIndex(name, ":")+1:]
+}
@@ -0,0 +1,80 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReadRepeatableFromRecursiveXsd(t *testing.T) {
+	cases := []struct {
+		name     string
+		xsd      string
+		expected RepeatableElements
+	}{
+		{
+			"element referencing itself",
+			`<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema">
+				<xs:element name="node">
+					<xs:complexType>
+						<xs:sequence>
+							<xs:element name="label" type="xs:string"/>
+							<xs:element ref="node" minOccurs="0" maxOccurs="unbounded"/>
+						</xs:sequence>
+					</xs:complexType>
+				</xs:element>
+			</xs:schema>`,
+			RepeatableElements{"node.node": true},
+		},
+		{
+			"elements referencing each other",
+			`<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema">
+				<xs:element name="folder">
+					<xs:complexType>
+						<xs:sequence>
+							<xs:element ref="content" maxOccurs="unbounded"/>
+						</xs:sequence>
+					</xs:complexType>
+				</xs:element>
+				<xs:element name="content">
+					<xs:complexType>
+						<xs:choice>
+							<xs:element name="file" type="xs:string" maxOccurs="5"/>
+							<xs:element ref="folder"/>
+						</xs:choice>
+					</xs:complexType>
+				</xs:element>
+			</xs:schema>`,
+			RepeatableElements{
+				"folder.content":         true,
+				"folder.content.file":    true,
+				"content.file":           true,
+				"content.folder.content": true,
+			},
+		},
+		{
+			"recursive type",
+			`<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema">
+				<xs:complexType name="Tree">
+					<xs:sequence>
+						<xs:element name="branch" type="Tree" minOccurs="0" maxOccurs="unbounded"/>
+					</xs:sequence>
+				</xs:complexType>
+				<xs:element name="tree" type="Tree"/>
+			</xs:schema>`,
+			RepeatableElements{"tree.branch": true},
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			repeatable, errXsd := ReadRepeatableFromXsd([]byte(testCase.xsd))
+			if errXsd != nil {
+				t.Fatalf("unexpected error: %s", errXsd)
+			}
+
+			if !reflect.DeepEqual(repeatable, testCase.expected) {
+				t.Errorf("expected %v, got %v", testCase.expected, repeatable)
+			}
+		})
+	}
+}
@@ -0,0 +1,328 @@
+package core
+
+import (
+	_ "embed" // for the schema of the ID params
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//------------------------------------------------------------------------------
+// Here we validate the ID params against their JSON schema, to catch the typos
+// early - like "use" instead of "_use" - with the location of the faulty node
+//------------------------------------------------------------------------------
+
+//go:embed idparams.schema.json
+var idParamsSchema []byte
+
+// the maximum edit distance for an unknown property to be considered as a typo for a known one
+const schemaMAXTYPO = 2
+
+// IdParamsSchema returns the JSON schema describing the ID params
+func IdParamsSchema() []byte {
+	return idParamsSchema
+}
+
+// validateIdParams checks the given JSON representation of ID params against their schema, and returns all the errors found
+func validateIdParams(idParamsBytes []byte) []string {
+	var value interface{}
+
+	if errUnmarsh := json.Unmarshal(idParamsBytes, &value); errUnmarsh != nil {
+		return []string{describeJsonError(idParamsBytes, errUnmarsh)}
+	}
+
+	schema := map[string]interface{}{}
+
+	if errUnmarsh := json.Unmarshal(idParamsSchema, &schema); errUnmarsh != nil {
+		panic(fmt.Errorf("the embedded ID params schema is not a valid JSON: %s", errUnmarsh))
+	}
+
+	validator := &schemaValidator{root: schema}
+	validator.validate(schema, value, "")
+
+	sort.Strings(validator.errors)
+
+	return validator.errors
+}
+
+// describeJsonError adds the line and column to a JSON syntax error, when possible
+func describeJsonError(data []byte, err error) string {
+	offset := int64(-1)
+
+	//nolint:errorlint
+	switch jsonErr := err.(type) {
+	case *json.SyntaxError:
+		offset = jsonErr.Offset
+	case *json.UnmarshalTypeError:
+		offset = jsonErr.Offset
+	}
+
+	if offset < 0 || offset > int64(len(data)) {
+		return fmt.Sprintf("not a valid JSON: %s", err)
+	}
+
+	before := data[:offset]
+	line := strings.Count(string(before), "\n") + 1
+	column := len(before) - strings.LastIndex(string(before), "\n")
+
+	return fmt.Sprintf("not a valid JSON, at line %d, column %d: %s", line, column, err)
+}
+
+//------------------------------------------------------------------------------
+// A minimal validator, handling the subset of JSON schema used by our schema
+//------------------------------------------------------------------------------
+
+type schemaValidator struct {
+	root   map[string]interface{} // the root schema, to resolve the references
+	errors []string               // the errors found so far
+}
+
+// addError records an error for the node at the given JSON pointer
+func (thisValidator *schemaValidator) addError(pointer string, format string, args ...interface{}) {
+	if pointer == "" {
+		pointer = "/"
+	}
+
+	thisValidator.errors = append(thisValidator.errors, fmt.Sprintf("%s: %s", pointer, fmt.Sprintf(format, args...)))
+}
+
+// resolve returns the schema designated by a local reference, like "#/$defs/idParam"
+func (thisValidator *schemaValidator) resolve(ref string) map[string]interface{} {
+	var current interface{} = thisValidator.root
+
+	for _, token := range strings.Split(strings.TrimPrefix(ref, "#"), "/")[1:] {
+		currentMap, isMap := current.(map[string]interface{})
+		if !isMap {
+			panic(fmt.Errorf("unresolvable schema reference '%s'", ref))
+		}
+
+		current = currentMap[unescapePointerToken(token)]
+	}
+
+	result, isMap := current.(map[string]interface{})
+	if !isMap {
+		panic(fmt.Errorf("unresolvable schema reference '%s'", ref))
+	}
+
+	return result
+}
+
+// validate checks the given value against the given schema; returns the names of the properties evaluated by this schema,
+// which is needed to handle "unevaluatedProperties"
+func (thisValidator *schemaValidator) validate(schema map[string]interface{}, value interface{}, pointer string) map[string]bool {
+	evaluated := map[string]bool{}
+
+	if ref, hasRef := schema["$ref"].(string); hasRef {
+		for prop := range thisValidator.validate(thisValidator.resolve(ref), value, pointer) {
+			evaluated[prop] = true
+		}
+	}
+
+	// no need to go further if the type is wrong
+	if expected, hasType := schema["type"].(string); hasType {
+		if actual := getJsonType(value); actual != expected && !(expected == "number" && actual == "integer") {
+			thisValidator.addError(pointer, "expected %s, but got %s", withArticle(expected), withArticle(actual))
+
+			return evaluated
+		}
+	}
+
+	if enum, hasEnum := schema["enum"].([]interface{}); hasEnum {
+		thisValidator.validateEnum(enum, value, pointer)
+	}
+
+	if minimum, hasMin := schema["minimum"].(float64); hasMin {
+		if number, isNumber := value.(float64); isNumber && number < minimum {
+			thisValidator.addError(pointer, "%v is lower than the minimum %v", number, minimum)
+		}
+	}
+
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		thisValidator.validateObject(schema, typedValue, pointer, evaluated)
+	case []interface{}:
+		if items, hasItems := schema["items"].(map[string]interface{}); hasItems {
+			for index, item := range typedValue {
+				thisValidator.validate(items, item, pointer+"/"+strconv.Itoa(index))
+			}
+		}
+	}
+
+	return evaluated
+}
+
+func (thisValidator *schemaValidator) validateEnum(enum []interface{}, value interface{}, pointer string) {
+	allowed := []string{}
+
+	for _, candidate := range enum {
+		if sameJsonValues(candidate, value) {
+			return
+		}
+
+		allowed = append(allowed, fmt.Sprintf("%v", candidate))
+	}
+
+	thisValidator.addError(pointer, "'%v' is not one of: %s", value, strings.Join(allowed, ", "))
+}
+
+func (thisValidator *schemaValidator) validateObject(schema, value map[string]interface{}, pointer string, evaluated map[string]bool) {
+	properties, _ := schema["properties"].(map[string]interface{})
+
+	if required, hasRequired := schema["required"].([]interface{}); hasRequired {
+		for _, prop := range required {
+			if _, present := value[fmt.Sprintf("%v", prop)]; !present {
+				thisValidator.addError(pointer, "missing required property '%v'", prop)
+			}
+		}
+	}
+
+	for prop, propValue := range value {
+		propPointer := pointer + "/" + escapePointerToken(prop)
+
+		if propSchema, known := properties[prop].(map[string]interface{}); known {
+			thisValidator.validate(propSchema, propValue, propPointer)
+			evaluated[prop] = true
+
+			continue
+		}
+
+		switch additional := schema["additionalProperties"].(type) {
+		case map[string]interface{}:
+			thisValidator.validate(additional, propValue, propPointer)
+			evaluated[prop] = true
+		case bool:
+			if !additional {
+				thisValidator.addError(propPointer, "unknown property '%s'%s", prop, suggestProperty(prop, thisValidator.getKnownProperties(schema)))
+			}
+		}
+	}
+
+	if unevaluated, hasUneval := schema["unevaluatedProperties"].(bool); hasUneval && !unevaluated {
+		for prop := range value {
+			if !evaluated[prop] {
+				thisValidator.addError(pointer+"/"+escapePointerToken(prop), "unknown property '%s'%s", prop,
+					suggestProperty(prop, thisValidator.getKnownProperties(schema)))
+			}
+		}
+	}
+}
+
+// getKnownProperties returns the names of the properties declared by a schema, directly or through references
+func (thisValidator *schemaValidator) getKnownProperties(schema map[string]interface{}) []string {
+	known := []string{}
+
+	if ref, hasRef := schema["$ref"].(string); hasRef {
+		known = append(known, thisValidator.getKnownProperties(thisValidator.resolve(ref))...)
+	}
+
+	if properties, hasProps := schema["properties"].(map[string]interface{}); hasProps {
+		for prop := range properties {
+			known = append(known, prop)
+		}
+	}
+
+	sort.Strings(known)
+
+	return known
+}
+
+//------------------------------------------------------------------------------
+// Utility functions
+//------------------------------------------------------------------------------
+
+// getJsonType returns the JSON schema type of a value, as unmarshalled by the json package
+func getJsonType(value interface{}) string {
+	switch typedValue := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if typedValue == float64(int64(typedValue)) {
+			return "integer"
+		}
+
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+func withArticle(jsonType string) string {
+	switch jsonType {
+	case "null":
+		return jsonType
+	case "array", "object", "integer":
+		return "an " + jsonType
+	default:
+		return "a " + jsonType
+	}
+}
+
+// suggestProperty returns a hint about the known property the given unknown one is probably a typo for, if any
+func suggestProperty(prop string, known []string) string {
+	best, bestDist := "", schemaMAXTYPO+1
+
+	for _, candidate := range known {
+		if dist := editDistance(strings.ToLower(prop), strings.ToLower(candidate)); dist < bestDist {
+			best, bestDist = candidate, dist
+		}
+	}
+
+	if best == "" {
+		return fmt.Sprintf(" (known properties: %s)", strings.Join(known, ", "))
+	}
+
+	return fmt.Sprintf(" - did you mean '%s' ?", best)
+}
+
+// editDistance computes the Levenshtein distance between 2 strings
+func editDistance(str1, str2 string) int {
+	runes1, runes2 := []rune(str1), []rune(str2)
+	previous := make([]int, len(runes2)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(runes1); i++ {
+		current := make([]int, len(runes2)+1)
+		current[0] = i
+
+		for j := 1; j <= len(runes2); j++ {
+			cost := 1
+			if runes1[i-1] == runes2[j-1] {
+				cost = 0
+			}
+
+			current[j] = minInt(minInt(previous[j]+1, current[j-1]+1), previous[j-1]+cost)
+		}
+
+		previous = current
+	}
+
+	return previous[len(runes2)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
+
+// escapePointerToken escapes a property name to be used in a JSON pointer
+func escapePointerToken(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+func unescapePointerToken(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+}
@@ -0,0 +1,185 @@
+package core
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+	"time"
+)
+
+//------------------------------------------------------------------------------
+// Here are the built-in comparators, which the ID params can select for the
+// simple values at a given path, with "as": datetimes compared as instants,
+// exact decimals, UUIDs whatever their case, and ISO durations by length
+//------------------------------------------------------------------------------
+
+// the built-in comparators
+const (
+	asDATETIME = "datetime"
+	asDECIMAL  = "decimal"
+	asUUID     = "uuid"
+	asDURATION = "duration"
+)
+
+// the layouts used to parse the datetimes, when none are given in the ID params; the datetimes without time zone are considered in UTC
+var defaultDatetimeLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999", "2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999", "2006-01-02", time.RFC1123Z, time.RFC1123}
+
+// compareBuiltin compares 2 simple, non-nil values with the built-in comparator selected in this ID param, if any; returns false if there's none
+func (thisParam *IdentificationParameter) compareBuiltin(obj1, obj2 interface{}) (Comparison, bool) {
+	if obj1 == nil || obj2 == nil || !isSimpleValue(obj1) || !isSimpleValue(obj2) {
+		return nil, false
+	}
+
+	comparator := thisParam.getBuiltinComparator()
+	if comparator == nil {
+		return nil, false
+	}
+
+	return applyComparator(comparator, obj1, obj2), true
+}
+
+// getBuiltinComparator returns the built-in comparator selected for this ID param's path, if any
+func (thisParam *IdentificationParameter) getBuiltinComparator() Comparator {
+	if thisParam == nil {
+		return nil
+	}
+
+	switch thisParam.As {
+	case asDATETIME:
+		layouts := thisParam.Layouts
+		if len(layouts) == 0 {
+			layouts = defaultDatetimeLayouts
+		}
+
+		return semanticComparator(asDATETIME, func(value interface{}) (interface{}, bool) { return parseDatetime(value, layouts) },
+			func(parsed1, parsed2 interface{}) bool { return parsed1.(time.Time).Equal(parsed2.(time.Time)) })
+
+	case asDECIMAL:
+		return semanticComparator(asDECIMAL, parseDecimal,
+			func(parsed1, parsed2 interface{}) bool { return parsed1.(*big.Rat).Cmp(parsed2.(*big.Rat)) == 0 })
+
+	case asUUID:
+		return semanticComparator(asUUID, parseUuid,
+			func(parsed1, parsed2 interface{}) bool { return parsed1 == parsed2 })
+
+	case asDURATION:
+		return semanticComparator(asDURATION, parseDuration,
+			func(parsed1, parsed2 interface{}) bool { return parsed1.(*big.Rat).Cmp(parsed2.(*big.Rat)) == 0 })
+	}
+
+	return nil
+}
+
+// semanticComparator builds a comparator which parses the values before comparing them; the values that cannot be parsed are only equal
+// to themselves, and the difference then comes with a message
+func semanticComparator(kind string, parse func(interface{}) (interface{}, bool), equal func(interface{}, interface{}) bool) Comparator {
+	return func(value1, value2 interface{}) (bool, string) {
+		parsed1, ok1 := parse(value1)
+		parsed2, ok2 := parse(value2)
+
+		if ok1 && ok2 {
+			return equal(parsed1, parsed2), ""
+		}
+
+		if value1 == value2 {
+			return true, ""
+		}
+
+		if !ok1 {
+			return false, fmt.Sprintf("not a valid %s: %v", kind, value1)
+		}
+
+		return false, fmt.Sprintf("not a valid %s: %v", kind, value2)
+	}
+}
+
+// parseDatetime parses a datetime with the first layout that fits
+func parseDatetime(value interface{}, layouts []string) (interface{}, bool) {
+	str, isString := value.(string)
+	if !isString {
+		return nil, false
+	}
+
+	for _, layout := range layouts {
+		if datetime, errParse := time.Parse(layout, strings.TrimSpace(str)); errParse == nil {
+			return datetime, true
+		}
+	}
+
+	return nil, false
+}
+
+// parseDecimal parses a decimal number exactly - see getExactNumber
+func parseDecimal(value interface{}) (interface{}, bool) {
+	if str, isString := value.(string); isString {
+		return new(big.Rat).SetString(strings.TrimSpace(str))
+	}
+
+	return getExactNumber(value)
+}
+
+// the UUIDs can be written with braces, or as URNs
+var uuidRegexp = regexp.MustCompile(`^(?i)(?:urn:uuid:)?\{?([0-9a-f]{8})-?([0-9a-f]{4})-?([0-9a-f]{4})-?([0-9a-f]{4})-?([0-9a-f]{12})\}?$`)
+
+// parseUuid returns the hexadecimal digits of a UUID, in lower case
+func parseUuid(value interface{}) (interface{}, bool) {
+	str, isString := value.(string)
+	if !isString {
+		return nil, false
+	}
+
+	parts := uuidRegexp.FindStringSubmatch(strings.TrimSpace(str))
+	if parts == nil {
+		return nil, false
+	}
+
+	return strings.ToLower(strings.Join(parts[1:], "")), true
+}
+
+// an ISO 8601 duration, e.g. "P1DT12H", "PT0.5S" or "P2W"
+var durationRegexp = regexp.MustCompile(`^([-+])?P(?:([\d.]+)Y)?(?:([\d.]+)M)?(?:([\d.]+)W)?(?:([\d.]+)D)?(?:T(?:([\d.]+)H)?(?:([\d.]+)M)?(?:([\d.]+)S)?)?$`)
+
+// the number of seconds in each part of an ISO 8601 duration; a year counts 365 days, and a month 30 days
+var durationUnits = []int64{365 * 86400, 30 * 86400, 7 * 86400, 86400, 3600, 60, 1} //nolint:gomnd
+
+// parseDuration returns the total length of a duration, in seconds; the Go durations, e.g. "1h30m", are accepted too
+func parseDuration(value interface{}) (interface{}, bool) {
+	str, isString := value.(string)
+	if !isString {
+		return nil, false
+	}
+
+	str = strings.TrimSpace(str)
+
+	parts := durationRegexp.FindStringSubmatch(str)
+	if parts == nil || str == "P" || strings.HasSuffix(str, "T") {
+		if duration, errParse := time.ParseDuration(str); errParse == nil {
+			return new(big.Rat).SetFrac64(int64(duration), int64(time.Second)), true
+		}
+
+		return nil, false
+	}
+
+	total := new(big.Rat)
+
+	for i, unit := range durationUnits {
+		if parts[i+2] == "" {
+			continue
+		}
+
+		amount, ok := new(big.Rat).SetString(parts[i+2])
+		if !ok {
+			return nil, false
+		}
+
+		total.Add(total, amount.Mul(amount, new(big.Rat).SetInt64(unit)))
+	}
+
+	if parts[1] == "-" {
+		total.Neg(total)
+	}
+
+	return total, true
+}
@@ -0,0 +1,275 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+//------------------------------------------------------------------------------
+// Here we pair the elements of 2 arrays by similarity, for the arrays whose
+// elements do not have any stable ID - like free-form remarks, or addresses
+//------------------------------------------------------------------------------
+
+const (
+	matchSIMILARITY   = "similarity" // the value of the "match" ID param property to pair the array elements by similarity
+	similarityMAXDIST = 0.5          // the default maximum distance for 2 elements to be paired
+)
+
+// matchesBySimilarity tells if the array elements at this ID param's path are paired by similarity, rather than with keys
+func (thisParam *IdentificationParameter) matchesBySimilarity() bool {
+	return thisParam != nil && thisParam.Match == matchSIMILARITY
+}
+
+// getMaxDist returns the maximum distance for 2 elements to be paired by similarity
+func (thisParam *IdentificationParameter) getMaxDist() float64 {
+	if thisParam.MaxDist > 0 {
+		return thisParam.MaxDist
+	}
+
+	return similarityMAXDIST
+}
+
+// compareSlicesBySimilarity pairs the elements of the 2 slices with the lowest total distance, and compares the paired elements;
+// the elements are keyed with their indexes, e.g. "#2 => #3" for elements paired at different positions, "#2 => -" for an element only
+// found in the first slice, and "- => #3" for an element only found in the second one
+func compareSlicesBySimilarity(root1, root2 *JsonEntity, idParam *IdentificationParameter, slice1, slice2 []map[string]interface{},
+	options *ComparisonOptions, currentPathValue string) (Comparison, error) {
+	// the distances between all the elements
+	distances := make([][]float64, len(slice1))
+
+	for i, element1 := range slice1 {
+		distances[i] = make([]float64, len(slice2))
+		leaves1 := getLeaves(element1, "", map[string]interface{}{})
+
+		for j, element2 := range slice2 {
+			distances[i][j] = getLeavesDistance(leaves1, getLeaves(element2, "", map[string]interface{}{}))
+		}
+	}
+
+	// the best assignment
+	ent1 := &JsonEntity{parent: root1, values: map[string]interface{}{}}
+	ent2 := &JsonEntity{parent: root2, values: map[string]interface{}{}}
+
+	paired1, paired2 := map[int]bool{}, map[int]bool{}
+	maxDist := idParam.getMaxDist()
+
+	for i, j := range assignMinCost(distances) {
+		if j < 0 || distances[i][j] > maxDist {
+			continue
+		}
+
+		key := fmt.Sprintf("#%d", i)
+		if i != j {
+			key = fmt.Sprintf("#%d => #%d", i, j)
+		}
+
+		ent1.values[key], ent2.values[key] = slice1[i], slice2[j]
+		paired1[i], paired2[j] = true, true
+	}
+
+	// the elements left alone
+	for i, element1 := range slice1 {
+		if !paired1[i] {
+			ent1.values[fmt.Sprintf("#%d => -", i)] = element1
+		}
+	}
+
+	for j, element2 := range slice2 {
+		if !paired2[j] {
+			ent2.values[fmt.Sprintf("- => #%d", j)] = element2
+		}
+	}
+
+	return compareJsonEntities(idParam, ent1, ent2, options, currentPathValue, true)
+}
+
+//------------------------------------------------------------------------------
+// Computing the distance between 2 elements
+//------------------------------------------------------------------------------
+
+// getLeaves flattens an object into its leaf values, by path
+func getLeaves(obj interface{}, currentPath string, leaves map[string]interface{}) map[string]interface{} {
+	switch typedObj := obj.(type) {
+	case map[string]interface{}:
+		for key, value := range typedObj {
+			if key != objALIAS {
+				getLeaves(value, currentPath+">"+key, leaves)
+			}
+		}
+
+	case []interface{}:
+		for index, value := range typedObj {
+			getLeaves(value, currentPath+">"+strconv.Itoa(index), leaves)
+		}
+
+	case []map[string]interface{}:
+		for index, value := range typedObj {
+			getLeaves(value, currentPath+">"+strconv.Itoa(index), leaves)
+		}
+
+	case []string:
+		for index, value := range typedObj {
+			leaves[currentPath+">"+strconv.Itoa(index)] = value
+		}
+
+	default:
+		leaves[currentPath] = obj
+	}
+
+	return leaves
+}
+
+// getLeavesDistance computes the distance, between 0 and 1, between 2 flattened objects: the average distance between their leaves,
+// a leaf only found on one side being at distance 1
+func getLeavesDistance(leaves1, leaves2 map[string]interface{}) float64 {
+	paths := map[string]bool{}
+
+	for leafPath := range leaves1 {
+		paths[leafPath] = true
+	}
+
+	for leafPath := range leaves2 {
+		paths[leafPath] = true
+	}
+
+	if len(paths) == 0 {
+		return 0
+	}
+
+	total := 0.0
+
+	for leafPath := range paths {
+		value1, in1 := leaves1[leafPath]
+		value2, in2 := leaves2[leafPath]
+
+		total += getLeafDistance(value1, value2, in1 && in2)
+	}
+
+	return total / float64(len(paths))
+}
+
+// getLeafDistance computes the distance, between 0 and 1, between 2 leaf values; the strings can be partially similar
+func getLeafDistance(value1, value2 interface{}, both bool) float64 {
+	if !both {
+		return 1
+	}
+
+	string1, isString1 := value1.(string)
+	string2, isString2 := value2.(string)
+
+	if isString1 && isString2 {
+		if string1 == string2 {
+			return 0
+		}
+
+		length := math.Max(float64(len([]rune(string1))), float64(len([]rune(string2))))
+
+		return float64(editDistance(string1, string2)) / length
+	}
+
+	if sameJsonValues(value1, value2) {
+		return 0
+	}
+
+	return 1
+}
+
+//------------------------------------------------------------------------------
+// Finding the assignment with the minimum cost - the Hungarian algorithm
+//------------------------------------------------------------------------------
+
+// assignMinCost returns, for each row of the given cost matrix, the column it's assigned to (or -1), so that the total cost is minimal
+func assignMinCost(costs [][]float64) []int {
+	nbRows := len(costs)
+	if nbRows == 0 {
+		return []int{}
+	}
+
+	nbCols := len(costs[0])
+
+	// the algorithm needs at least as many columns as rows
+	if nbRows > nbCols {
+		transposed := make([][]float64, nbCols)
+
+		for j := range transposed {
+			transposed[j] = make([]float64, nbRows)
+			for i := range costs {
+				transposed[j][i] = costs[i][j]
+			}
+		}
+
+		result := make([]int, nbRows)
+		for i := range result {
+			result[i] = -1
+		}
+
+		for j, i := range assignMinCost(transposed) {
+			result[i] = j
+		}
+
+		return result
+	}
+
+	// potentials for the rows and columns, and the row assigned to each column - all 1-indexed, the column 0 being a sentinel
+	rowPot, colPot := make([]float64, nbRows+1), make([]float64, nbCols+1)
+	colRow, way := make([]int, nbCols+1), make([]int, nbCols+1)
+
+	for row := 1; row <= nbRows; row++ {
+		colRow[0] = row
+		col0 := 0
+		minValues := make([]float64, nbCols+1)
+		used := make([]bool, nbCols+1)
+
+		for j := range minValues {
+			minValues[j] = math.Inf(1)
+		}
+
+		for colRow[col0] != 0 {
+			used[col0] = true
+			row0, delta, col1 := colRow[col0], math.Inf(1), 0
+
+			for j := 1; j <= nbCols; j++ {
+				if used[j] {
+					continue
+				}
+
+				if current := costs[row0-1][j-1] - rowPot[row0] - colPot[j]; current < minValues[j] {
+					minValues[j], way[j] = current, col0
+				}
+
+				if minValues[j] < delta {
+					delta, col1 = minValues[j], j
+				}
+			}
+
+			for j := 0; j <= nbCols; j++ {
+				if used[j] {
+					rowPot[colRow[j]] += delta
+					colPot[j] -= delta
+				} else {
+					minValues[j] -= delta
+				}
+			}
+
+			col0 = col1
+		}
+
+		// updating the assignment along the augmenting path
+		for col0 != 0 {
+			col1 := way[col0]
+			colRow[col0] = colRow[col1]
+			col0 = col1
+		}
+	}
+
+	result := make([]int, nbRows)
+
+	for j := 1; j <= nbCols; j++ {
+		if colRow[j] != 0 {
+			result[colRow[j]-1] = j - 1
+		}
+	}
+
+	return result
+}
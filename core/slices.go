@@ -1,6 +1,7 @@
 package core
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"strconv"
@@ -46,8 +47,16 @@ func compareSlicesOfObjects(root1, root2 *JsonEntity, idParam *IdentificationPar
 		panic(fmt.Sprintf("ID param is nil at path: %s", currentPathValue))
 	}
 
+	// pairing the elements by similarity, rather than with keys ?
+	if slice1Kind == reflect.Map && idParam.matchesBySimilarity() {
+		maps1, _ := toMap(slice1)
+		maps2, _ := toMap(slice2)
+
+		return compareSlicesBySimilarity(root1, root2, idParam, maps1, maps2, options, currentPathValue)
+	}
+
 	// should we clear the identical elements before performing a comparison on the diverging elements only ?
-	if idParam != nil && !idParam.Keep {
+	if idParam != nil && !idParam.Keep && !idParam.Moves {
 		slice1, slice2 = clearObjectsSiblings(slice1, slice2, options, currentPathValue)
 
 		// we'll use the code lines above
@@ -71,7 +80,14 @@ func compareSlicesOfObjects(root1, root2 *JsonEntity, idParam *IdentificationPar
 	}
 
 	// we know how to deal with maps
-	return compareJsonEntities(idParam, ent1, ent2, options, currentPathValue, true)
+	comparison, errComp := compareJsonEntities(idParam, ent1, ent2, options, currentPathValue, true)
+
+	// reporting the moved elements ?
+	if errComp == nil && idParam.detectsMoves() {
+		comparison = addMoves(comparison, ent1, ent2)
+	}
+
+	return comparison, errComp
 }
 
 //nolint:cyclop,gocyclo,gocognit
@@ -94,15 +110,23 @@ func sliceToMapOfObjects(file int, root *JsonEntity, idParam *IdentificationPara
 			}
 		}
 
-	case reflect.String: // building a map of strings, using their values as keys
+	case reflect.String: // building a map of strings, using their values as keys - or of numbers, decoded exactly
 		for _, word := range slice {
-			ent.values[word.(string)] = word
+			if number, isNumber := word.(json.Number); isNumber {
+				ent.values[formatExactNumber(number)] = word
+			} else {
+				ent.values[idParam.getNormalization(options).apply(word.(string))] = word
+			}
 		}
 
 	case reflect.Map: // building a map of objects, using their id prop as keys
 		// using the value targeted by the ID property for each object as its ID
-		for _, object := range slice {
-			key := idParam.BuildUniqueKey(entityFrom(object, root), currentPathValue)
+		for index, object := range slice {
+			key := idParam.doBuildUniqueKey(entityFrom(object, root), currentPathValue, options.newKeyTrace(idParam, file, currentPathValue, index))
+
+			if idParam.detectsMoves() {
+				ent.setPosition(key, index)
+			}
 
 			// we should never up with an empty key
 			if key == "" {
@@ -243,8 +267,13 @@ func compareSlicesOfMaps(root1, root2 *JsonEntity, idParam *IdentificationParame
 		panic(fmt.Sprintf("Nil ID param at path: %s", currentPathValue))
 	}
 
+	// pairing the elements by similarity, rather than with keys ?
+	if idParam.matchesBySimilarity() {
+		return compareSlicesBySimilarity(root1, root2, idParam, slice1, slice2, options, currentPathValue)
+	}
+
 	// should we clear the identical elements before performing a comparison on the diverging elements only ?
-	if !idParam.Keep {
+	if !idParam.Keep && !idParam.Moves {
 		slice1, slice2 = clearMapsSiblings(slice1, slice2, options, currentPathValue)
 
 		// we'll use the code lines above
@@ -268,15 +297,26 @@ func compareSlicesOfMaps(root1, root2 *JsonEntity, idParam *IdentificationParame
 	}
 
 	// we know how to deal with maps
-	return compareJsonEntities(idParam, map1, map2, options, currentPathValue, true)
+	comparison, errComp := compareJsonEntities(idParam, map1, map2, options, currentPathValue, true)
+
+	// reporting the moved elements ?
+	if errComp == nil && idParam.detectsMoves() {
+		comparison = addMoves(comparison, map1, map2)
+	}
+
+	return comparison, errComp
 }
 
 func sliceToMapOfMaps(file int, root *JsonEntity, idParam *IdentificationParameter, slice []map[string]interface{}, options *ComparisonOptions, currentPathValue string) (*JsonEntity, error) {
 	ent := &JsonEntity{parent: root, values: map[string]interface{}{}}
 
 	// using the value targeted by the ID property for each object as its ID
-	for _, mapInSlice := range slice {
-		key := idParam.BuildUniqueKey(entity(mapInSlice).from(root), currentPathValue)
+	for index, mapInSlice := range slice {
+		key := idParam.doBuildUniqueKey(entity(mapInSlice).from(root), currentPathValue, options.newKeyTrace(idParam, file, currentPathValue, index))
+
+		if idParam.detectsMoves() {
+			ent.setPosition(key, index)
+		}
 
 		// we should never up with an empty key
 		if key == "" {
@@ -372,14 +412,44 @@ func clearMapsSiblings(slice1In, slice2In []map[string]interface{}, options *Com
 //------------------------------------------------------------------------------
 
 func compareSlicesOfStrings(idParam *IdentificationParameter, slice1, slice2 []string, options *ComparisonOptions, currentPathValue string) (Comparison, error) {
-	return compareJsonEntities(idParam, sliceOfStringsToEntity(slice1), sliceOfStringsToEntity(slice2), options, currentPathValue, false)
+	normalization := idParam.getNormalization(options)
+
+	return compareJsonEntities(idParam, sliceOfStringsToEntity(slice1, normalization), sliceOfStringsToEntity(slice2, normalization), options, currentPathValue, false)
+}
+
+// alignSlicesOfElements turns a slice of strings into a slice of maps holding these strings with the given text key, if the other slice is a
+// slice of maps - e.g. when repeated XML elements only have some text in a file, but attributes or children in the other one
+func alignSlicesOfElements(obj1, obj2 interface{}, textKey string) (interface{}, interface{}) {
+	if strings1, isStrings := obj1.([]string); isStrings {
+		if _, isMaps := obj2.([]map[string]interface{}); isMaps {
+			return stringsToElements(strings1, textKey), obj2
+		}
+	}
+
+	if strings2, isStrings := obj2.([]string); isStrings {
+		if _, isMaps := obj1.([]map[string]interface{}); isMaps {
+			return obj1, stringsToElements(strings2, textKey)
+		}
+	}
+
+	return obj1, obj2
+}
+
+// stringsToElements turns some strings into maps holding them with the given text key
+func stringsToElements(slice []string, textKey string) []map[string]interface{} {
+	elements := make([]map[string]interface{}, len(slice))
+	for i, str := range slice {
+		elements[i] = map[string]interface{}{textKey: str}
+	}
+
+	return elements
 }
 
-func sliceOfStringsToEntity(slice []string) *JsonEntity {
+func sliceOfStringsToEntity(slice []string, normalization *StringNormalization) *JsonEntity {
 	ent := &JsonEntity{values: map[string]interface{}{}}
 
 	for _, value := range slice {
-		ent.values[value] = value
+		ent.values[normalization.apply(value)] = value
 	}
 
 	return ent
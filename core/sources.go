@@ -0,0 +1,172 @@
+package core
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+//------------------------------------------------------------------------------
+// Here we define the sources of files we can compare: folders, of course, but
+// also anything that can be seen as a folder
+//------------------------------------------------------------------------------
+
+// fileSource : something containing files that we can list, and read
+type fileSource interface {
+	getPath() string                                        // a representation of this source, for display purposes
+	listFiles(options *ComparisonOptions) ([]string, error) // the sorted names of the files within this source
+	readFile(name string) ([]byte, error)                   // reading the content of one of the listed files
+}
+
+//------------------------------------------------------------------------------
+// Pairing the files of 2 sources
+//------------------------------------------------------------------------------
+
+// filePair : 2 files that should be compared with each other
+type filePair struct {
+	nameOne string // the name of the file in the first source; empty if the file only exists in the second source
+	nameTwo string // the name of the file in the second source; empty if the file only exists in the first source
+}
+
+// key returns the name under which the comparison of the 2 files is reported
+func (thisPair *filePair) key() string {
+	if thisPair.nameOne == "" {
+		return thisPair.nameTwo
+	}
+
+	if thisPair.nameTwo == "" || thisPair.nameTwo == thisPair.nameOne {
+		return thisPair.nameOne
+	}
+
+	return thisPair.nameOne + " => " + thisPair.nameTwo
+}
+
+// pairFiles builds the couples of files to compare: files with the same name are paired, as well as the renamed files,
+// i.e. the files from the first source whose new name in the second source is given by `renames`; with the `PairCompressed` option,
+// the compressed files are paired with their uncompressed counterparts, e.g. "foo.json" with "foo.json.gz"; and the pairing parameter
+// can also pair files whose names share the same key
+func pairFiles(filesOne, filesTwo []string, renames map[string]string, options *ComparisonOptions) []*filePair {
+	// which files do we have in the 2nd source, for a given pairing key ?
+	filesMapTwo := map[string][]string{}
+	existTwo := map[string]bool{}
+
+	for _, fileTwo := range filesTwo {
+		key := options.getPairingKey(fileTwo)
+		filesMapTwo[key] = append(filesMapTwo[key], fileTwo)
+		existTwo[fileTwo] = true
+	}
+
+	// let's keep track of the files of the 2nd source that have found their pair
+	paired := map[string]bool{}
+
+	pairs := []*filePair{}
+
+	// going through the files of the first source
+	for _, fileOne := range filesOne {
+		// the candidates for being paired with this file
+		candidates := filesMapTwo[options.getPairingKey(fileOne)]
+
+		// has this file been renamed ? if so, its new name must be found - and not ignored - in the 2nd source
+		if renamed := renames[fileOne]; renamed != "" {
+			if existTwo[renamed] && !options.isIgnored(renamed) {
+				candidates = append([]string{renamed}, candidates...)
+			} else if !options.Silent {
+				options.Logger.Warn("The file '%s' was renamed into '%s', which cannot be found in the second source, or is ignored", fileOne, renamed)
+			}
+		}
+
+		// the first candidate that's still available is the one
+		fileTwo := ""
+
+		for _, candidate := range candidates {
+			if !paired[candidate] {
+				fileTwo = candidate
+
+				break
+			}
+		}
+
+		if fileTwo != "" {
+			paired[fileTwo] = true
+		}
+
+		pairs = append(pairs, &filePair{nameOne: fileOne, nameTwo: fileTwo})
+	}
+
+	// the files that only exist in the 2nd source
+	for _, fileTwo := range filesTwo {
+		if !paired[fileTwo] {
+			pairs = append(pairs, &filePair{nameTwo: fileTwo})
+		}
+	}
+
+	return pairs
+}
+
+//------------------------------------------------------------------------------
+// The simplest source: a folder
+//------------------------------------------------------------------------------
+
+type folderSource struct {
+	path string
+}
+
+func (thisFolder *folderSource) getPath() string {
+	return thisFolder.path
+}
+
+// returns a directory's list of files, including those of its sub-directories, with their relative paths - just like the entries of an archive,
+// or the files of a git revision, so that a folder can be compared with any other source
+func (thisFolder *folderSource) listFiles(options *ComparisonOptions) ([]string, error) {
+	filesSlice := []string{}
+
+	errWalk := filepath.WalkDir(thisFolder.path, func(fullPath string, entry fs.DirEntry, errEntry error) error {
+		if errEntry != nil {
+			return errEntry
+		}
+
+		if fullPath == thisFolder.path {
+			return nil
+		}
+
+		relPath, errRel := filepath.Rel(thisFolder.path, fullPath)
+		if errRel != nil {
+			return errRel
+		}
+
+		// an ignored directory is ignored with all its content
+		filename := filepath.ToSlash(relPath)
+		if options.isIgnored(filename) {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if !entry.IsDir() {
+			filesSlice = append(filesSlice, filename)
+		}
+
+		return nil
+	})
+	if errWalk != nil {
+		return nil, fmt.Errorf("Error while listing files at path '%s'. Cause: %s", thisFolder.path, errWalk)
+	}
+
+	// let's sort the file names
+	sort.Strings(filesSlice)
+
+	return filesSlice, nil
+}
+
+func (thisFolder *folderSource) readFile(name string) ([]byte, error) {
+	fileBytes, errRead := os.ReadFile(filepath.Join(thisFolder.path, filepath.FromSlash(name)))
+	if errRead != nil {
+		return nil, fmt.Errorf("Error while reading file '%s' in folder '%s'. Cause: %s", name, thisFolder.path, errRead)
+	}
+
+	return fileBytes, nil
+}
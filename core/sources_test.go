@@ -0,0 +1,193 @@
+package core
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// the files of a folder, and their content, by relative path
+var sourcesTestFiles = map[string]string{
+	"top.json":           `{"a":1}`,
+	"sub/in.json":        `{"b":2}`,
+	"sub/deeper/in.json": `{"c":3}`,
+	"skip/z.json":        `{"d":4}`,
+}
+
+// writeTestFolder writes the given files into a new temporary folder
+func writeTestFolder(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	folder := t.TempDir()
+
+	for name, content := range files {
+		fullPath := filepath.Join(folder, filepath.FromSlash(name))
+
+		if errMkdir := os.MkdirAll(filepath.Dir(fullPath), 0o755); errMkdir != nil {
+			t.Fatal(errMkdir)
+		}
+
+		if errWrite := os.WriteFile(fullPath, []byte(content), 0o600); errWrite != nil {
+			t.Fatal(errWrite)
+		}
+	}
+
+	return folder
+}
+
+// writeTestZip writes the given files into a new zip archive, with a directory entry for each folder, like the zip command does
+func writeTestZip(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	var buffer bytes.Buffer
+
+	zipWriter := zip.NewWriter(&buffer)
+	folders := map[string]bool{}
+
+	for name, content := range files {
+		if folder := filepath.ToSlash(filepath.Dir(name)); folder != "." && !folders[folder] {
+			folders[folder] = true
+
+			if _, errCreate := zipWriter.Create(folder + "/"); errCreate != nil {
+				t.Fatal(errCreate)
+			}
+		}
+
+		entryWriter, errCreate := zipWriter.Create(name)
+		if errCreate != nil {
+			t.Fatal(errCreate)
+		}
+
+		if _, errWrite := entryWriter.Write([]byte(content)); errWrite != nil {
+			t.Fatal(errWrite)
+		}
+	}
+
+	if errClose := zipWriter.Close(); errClose != nil {
+		t.Fatal(errClose)
+	}
+
+	return writeTestArchive(t, "files.zip", buffer.Bytes())
+}
+
+// writeTestTarGz writes the given files into a new tar.gz archive, with entries like "./sub/in.json", like the tar command does
+func writeTestTarGz(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	var buffer bytes.Buffer
+
+	gzipWriter := gzip.NewWriter(&buffer)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	for name, content := range files {
+		header := &tar.Header{Name: "./" + name, Mode: 0o600, Size: int64(len(content)), Typeflag: tar.TypeReg}
+
+		if errHeader := tarWriter.WriteHeader(header); errHeader != nil {
+			t.Fatal(errHeader)
+		}
+
+		if _, errWrite := tarWriter.Write([]byte(content)); errWrite != nil {
+			t.Fatal(errWrite)
+		}
+	}
+
+	if errClose := tarWriter.Close(); errClose != nil {
+		t.Fatal(errClose)
+	}
+
+	if errClose := gzipWriter.Close(); errClose != nil {
+		t.Fatal(errClose)
+	}
+
+	return writeTestArchive(t, "files.tar.gz", buffer.Bytes())
+}
+
+func writeTestArchive(t *testing.T, name string, content []byte) string {
+	t.Helper()
+
+	archivePath := filepath.Join(t.TempDir(), name)
+
+	if errWrite := os.WriteFile(archivePath, content, 0o600); errWrite != nil {
+		t.Fatal(errWrite)
+	}
+
+	return archivePath
+}
+
+func newSourcesTestOptions(ignored string) *ComparisonOptions {
+	return newTestOptions("{}", func(options *ComparisonOptions) {
+		options.NParallel = 2
+		options.IgnoredString = ignored
+	})
+}
+
+func TestListFilesOfFolder(t *testing.T) {
+	folder := writeTestFolder(t, sourcesTestFiles)
+
+	cases := []struct {
+		ignored  string
+		expected []string
+	}{
+		{"", []string{"skip/z.json", "sub/deeper/in.json", "sub/in.json", "top.json"}},
+		{"skip", []string{"sub/deeper/in.json", "sub/in.json", "top.json"}},
+		{"sub/deeper", []string{"skip/z.json", "sub/in.json", "top.json"}},
+		{"in.json,skip", []string{"top.json"}},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.ignored, func(t *testing.T) {
+			files, errList := (&folderSource{path: folder}).listFiles(newSourcesTestOptions(testCase.ignored))
+			if errList != nil {
+				t.Fatalf("unexpected error: %s", errList)
+			}
+
+			if !reflect.DeepEqual(files, testCase.expected) {
+				t.Errorf("expected %v, got %v", testCase.expected, files)
+			}
+		})
+	}
+}
+
+func TestCompareFolderWithArchives(t *testing.T) {
+	folder := writeTestFolder(t, sourcesTestFiles)
+
+	// the archives have the same files, except for 1 modified, 1 missing, and 1 added file
+	archiveFiles := map[string]string{}
+	for name, content := range sourcesTestFiles {
+		archiveFiles[name] = content
+	}
+
+	archiveFiles["sub/deeper/in.json"] = `{"c":4}`
+	archiveFiles["sub/new.json"] = `{}`
+	delete(archiveFiles, "top.json")
+
+	for _, archive := range []string{writeTestZip(t, archiveFiles), writeTestTarGz(t, archiveFiles)} {
+		t.Run(filepath.Base(archive), func(t *testing.T) {
+			comparison, errComp := CompareFolders(folder, archive, newSourcesTestOptions("skip"))
+			if errComp != nil {
+				t.Fatalf("unexpected error: %s", errComp)
+			}
+
+			keys := []string{}
+			for key := range comparison {
+				keys = append(keys, key)
+			}
+
+			sort.Strings(keys)
+
+			if expected := []string{"sub/deeper/in.json", "sub/new.json", "top.json"}; !reflect.DeepEqual(keys, expected) {
+				t.Errorf("expected differences for %v, got %s", expected, toJson(t, comparison))
+			}
+
+			if expected, actual := `{"c":{"_one_":3,"_two_":4}}`, toJson(t, comparison["sub/deeper/in.json"].(Comparison)); actual != expected {
+				t.Errorf("expected %s, got %s", expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package core
+
+import (
+	"sort"
+	"strings"
+)
+
+//------------------------------------------------------------------------------
+// Here we aggregate the differences found when comparing many files, to know
+// which paths change the most
+//------------------------------------------------------------------------------
+
+// the maximum number of example files given for a path
+const summaryEXAMPLES = 3
+
+// Summary : an aggregated report of the differences found when comparing folders
+type Summary struct {
+	Totals *SummaryTotals    `json:"totals"`           // how many files in each situation
+	Paths  []*SummaryPath    `json:"paths,omitempty"`  // the top offending paths
+	Errors map[string]string `json:"errors,omitempty"` // the files that could not be compared, with the cause
+}
+
+// SummaryTotals : the number of files in each situation
+type SummaryTotals struct {
+	Files       int `json:"files"`       // the total number of (pairs of) files
+	Identical   int `json:"identical"`   // the number of pairs of files without any difference
+	Differing   int `json:"differing"`   // the number of pairs of files with at least one difference
+	OnlyOne     int `json:"onlyOne"`     // the number of files only found on the first side
+	OnlyTwo     int `json:"onlyTwo"`     // the number of files only found on the second side
+	Errored     int `json:"errored"`     // the number of pairs of files that could not be compared
+	Differences int `json:"differences"` // the total number of differences, across all the files
+}
+
+// SummaryPath : the differences of a given kind, found at a given normalized path
+type SummaryPath struct {
+	Path     string   `json:"path"`     // the path, with the ID-keyed segments normalized back to their ID param path
+	Kind     string   `json:"kind"`     // modified, added, removed, moved, coerced, or null
+	Count    int      `json:"count"`    // the number of differences
+	Files    int      `json:"files"`    // the number of files with such differences
+	Examples []string `json:"examples"` // some of these files
+
+	// technical properties
+	lastFile string
+}
+
+func (thisSummary *Summary) toComparison() Comparison {
+	return Comparison{"totals": thisSummary.Totals, "paths": thisSummary.Paths, "errors": thisSummary.Errors}
+}
+
+//------------------------------------------------------------------------------
+// Building the summary
+//------------------------------------------------------------------------------
+
+// summarize builds the aggregated report for the given outcomes
+func summarize(outcomes []*fileOutcome, options *ComparisonOptions) *Summary {
+	summary := &Summary{Totals: &SummaryTotals{}, Errors: map[string]string{}}
+
+	// the differences, by normalized path and kind
+	paths := map[string]*SummaryPath{}
+
+	for _, outcome := range outcomes {
+		// with the 'StopAtFirst' option, some files may not have been handled
+		if outcome == nil {
+			continue
+		}
+
+		summary.Totals.Files++
+
+		switch {
+		case outcome.err != nil:
+			summary.Totals.Errored++
+			summary.Errors[outcome.pair.key()] = outcome.err.Error()
+
+		case outcome.pair.nameTwo == "":
+			summary.Totals.OnlyOne++
+
+		case outcome.pair.nameOne == "":
+			summary.Totals.OnlyTwo++
+
+		case !outcome.comparison.hasDiffs():
+			summary.Totals.Identical++
+
+		default:
+			summary.Totals.Differing++
+			summary.Totals.Differences += addSummaryPaths(paths, outcome.comparison, options.IdParams, "", false, outcome.pair.key())
+		}
+	}
+
+	// the most frequent differences first
+	for _, summaryPath := range paths {
+		summary.Paths = append(summary.Paths, summaryPath)
+	}
+
+	sort.Slice(summary.Paths, func(i, j int) bool {
+		if summary.Paths[i].Count != summary.Paths[j].Count {
+			return summary.Paths[i].Count > summary.Paths[j].Count
+		}
+
+		if summary.Paths[i].Path != summary.Paths[j].Path {
+			return summary.Paths[i].Path < summary.Paths[j].Path
+		}
+
+		return summary.Paths[i].Kind < summary.Paths[j].Kind
+	})
+
+	if len(summary.Paths) > options.SummaryTop {
+		summary.Paths = summary.Paths[:options.SummaryTop]
+	}
+
+	return summary
+}
+
+// addSummaryPaths recursively counts the differences found in the given comparison node, by normalized path;
+// `keyed` is true when the keys of the node are the keys built for the elements of an array, which are not part of the normalized path
+func addSummaryPaths(paths map[string]*SummaryPath, node map[string]interface{}, idParam *IdentificationParameter, currentPath string, keyed bool,
+	file string) int {
+	// we've reached a difference
+	if kind, isDiff := getDiffKind(node); isDiff {
+		countSummaryPath(paths, currentPath, kind, file)
+
+		return 1
+	}
+
+	nbDiffs := 0
+
+	for key, child := range node {
+		childMap, isMap := asMap(child)
+		if !isMap {
+			continue
+		}
+
+		// a move is a difference too, of the array element
+		if key == markerMOVED {
+			countSummaryPath(paths, currentPath, diffMOVED, file)
+			nbDiffs++
+
+			continue
+		}
+
+		// the keys of array elements are not part of the normalized path
+		if keyed {
+			nbDiffs += addSummaryPaths(paths, childMap, idParam, currentPath, false, file)
+
+			continue
+		}
+
+		// what's the ID param for this key, and the corresponding path ?
+		nextPath := strings.TrimPrefix(currentPath+"."+key, ".")
+
+		var nextIdParam *IdentificationParameter
+
+		if idParam != nil {
+			if nextIdParam = idParam.For[key]; nextIdParam != nil {
+				nextPath = strings.TrimPrefix(nextIdParam.toString(), ".")
+			}
+		}
+
+		nbDiffs += addSummaryPaths(paths, childMap, nextIdParam, nextPath, nextIdParam.identifiesElements(), file)
+	}
+
+	return nbDiffs
+}
+
+// countSummaryPath counts 1 more difference of the given kind at the given normalized path, in the given file
+func countSummaryPath(paths map[string]*SummaryPath, normalizedPath, kind, file string) {
+	summaryKey := normalizedPath + sepPIPE + kind
+
+	summaryPath := paths[summaryKey]
+	if summaryPath == nil {
+		summaryPath = &SummaryPath{Path: normalizedPath, Kind: kind}
+		paths[summaryKey] = summaryPath
+	}
+
+	summaryPath.Count++
+
+	if summaryPath.lastFile != file {
+		summaryPath.lastFile = file
+		summaryPath.Files++
+
+		if len(summaryPath.Examples) < summaryEXAMPLES {
+			summaryPath.Examples = append(summaryPath.Examples, file)
+		}
+	}
+}
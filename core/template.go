@@ -61,7 +61,7 @@ func (thisParam *IdentificationParameter) addAlias1(obj map[string]interface{},
 	if thisParam.buildTpl1 == nil {
 		var errParse error
 		if thisParam.buildTpl1, errParse = template.New(thisParam.toString()).Funcs(template.FuncMap{
-			"Display": display,
+			"Display": thisParam.display,
 			"Slice":   slice,
 		}).Parse(thisParam.getTpl1String()); errParse != nil {
 			panic(fmt.Sprintf("Invalid template '%s' at path: %s. Cause: %s", thisParam.getTpl1String(), thisParam.toString(), errParse))
@@ -86,7 +86,7 @@ func (thisParam *IdentificationParameter) addAliasN(objects []map[string]interfa
 	if thisParam.buildTplN == nil {
 		var errParse error
 		if thisParam.buildTplN, errParse = template.New(thisParam.toString()).Funcs(template.FuncMap{
-			"Display": display,
+			"Display": thisParam.display,
 			"Slice":   slice,
 		}).Parse(thisParam.getTplNString()); errParse != nil {
 			panic(fmt.Sprintf("Invalid template '%s' at path: %s. Cause: %s", thisParam.getTplNString(), thisParam.toString(), errParse))
@@ -124,6 +124,11 @@ func (thisParam *IdentificationParameter) getAlias(obj interface{}, currentPathV
 		}
 
 	case []map[string]interface{}:
+		// an empty array is displayed as is
+		if len(obj) == 0 {
+			return ""
+		}
+
 		_, ok := obj[0][objALIAS]
 		if ok {
 			aliases := []string{}
@@ -159,6 +164,10 @@ func (thisParam *IdentificationParameter) getAlias(obj interface{}, currentPathV
 }
 
 func toMap(obj []interface{}) ([]map[string]interface{}, bool) {
+	if len(obj) == 0 {
+		return nil, false
+	}
+
 	if _, ok := obj[0].(map[string]interface{}); ok {
 		objMap := make([]map[string]interface{}, len(obj))
 		for i, item := range obj {
@@ -172,16 +181,17 @@ func toMap(obj []interface{}) ([]map[string]interface{}, bool) {
 	return nil, false
 }
 
-func display(arg interface{}, path string, keys ...string) (result string) {
+func (thisParam *IdentificationParameter) display(arg interface{}, path string, keys ...string) (result string) {
 	if strings.TrimSpace(path) == "" {
-		return displayObj(arg, nil, 0, keys...)
+		return displayObj(thisParam.getXmlTextKey(), arg, nil, 0, keys...)
 	}
 
-	return displayObj(arg, strings.Split(path, "."), 0, keys...)
+	return displayObj(thisParam.getXmlTextKey(), arg, strings.Split(path, "."), 0, keys...)
 }
 
+// displayObj: the text of the XML elements having attributes is found with the given key
 //nolint:cyclop,gocyclo,gocognit
-func displayObj(arg interface{}, paths []string, pathIndex int, keys ...string) string {
+func displayObj(textKey string, arg interface{}, paths []string, pathIndex int, keys ...string) string {
 	if len(keys) == 0 {
 		return fmt.Sprintf("[no keys; using default display here] %v", arg)
 	}
@@ -216,7 +226,7 @@ func displayObj(arg interface{}, paths []string, pathIndex int, keys ...string)
 								}
 							} else {
 								obj, ok := currentObj[subKey].(map[string]interface{})
-								if val, hasText := obj["#text"]; ok && hasText {
+								if val, hasText := obj[textKey]; ok && hasText {
 									value = fmt.Sprintf("%s%v", value, val)
 								} else {
 									if target, ok := currentObj[subKey]; ok {
@@ -236,7 +246,7 @@ func displayObj(arg interface{}, paths []string, pathIndex int, keys ...string)
 		case []map[string]interface{}:
 			values := []string{}
 			for _, singleObj := range arg {
-				values = append(values, displayObj(singleObj, paths, pathIndex, keys...))
+				values = append(values, displayObj(textKey, singleObj, paths, pathIndex, keys...))
 			}
 
 			sort.Strings(values)
@@ -247,7 +257,7 @@ func displayObj(arg interface{}, paths []string, pathIndex int, keys ...string)
 			// we have to force the type "[]interface{}" into "map[string]interface{}" here
 			newMap, _ := toMap(arg)
 
-			return displayObj(newMap, paths, pathIndex, keys...)
+			return displayObj(textKey, newMap, paths, pathIndex, keys...)
 
 		default:
 			panic(fmt.Sprintf("[unhandled case (keys); using default display here] %v (%T).\n\nPaths: %v, index: %d.\n\nStack: %s",
@@ -258,13 +268,13 @@ func displayObj(arg interface{}, paths []string, pathIndex int, keys ...string)
 	// else, we've yet to ge deeper into the data ==> we're going down the paths here
 	switch arg := arg.(type) {
 	case map[string]interface{}:
-		return displayObj(arg[paths[pathIndex]], paths, pathIndex+1, keys...)
+		return displayObj(textKey, arg[paths[pathIndex]], paths, pathIndex+1, keys...)
 
 	case []map[string]interface{}:
 		values := []string{}
 
 		for _, singleObj := range arg {
-			values = append(values, displayObj(singleObj[paths[pathIndex]], paths, pathIndex+1, keys...))
+			values = append(values, displayObj(textKey, singleObj[paths[pathIndex]], paths, pathIndex+1, keys...))
 		}
 
 		sort.Strings(values)
@@ -274,7 +284,7 @@ func displayObj(arg interface{}, paths []string, pathIndex int, keys ...string)
 	case []interface{}:
 		newMap, _ := toMap(arg)
 
-		return displayObj(newMap, paths, pathIndex, keys...)
+		return displayObj(textKey, newMap, paths, pathIndex, keys...)
 
 	default:
 		panic(fmt.Sprintf("[unhandled case (path); using default display here] %v (%T).\n\nPaths: %v, index: %d.\n\nStack: %s",
@@ -1,6 +1,7 @@
 package core
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"strings"
@@ -19,26 +20,43 @@ const (
 
 //buildUniqueKey tries to build a unique key for the given object, according to what's configured on the given ID param
 func (thisParam *IdentificationParameter) BuildUniqueKey(ent *JsonEntity, currentPathValue string) (result string) {
-	return thisParam.doBuildUniqueKey(ent, currentPathValue)
+	return thisParam.doBuildUniqueKey(ent, currentPathValue, nil)
 }
 
+// doBuildUniqueKey: the given trace, if not nil, records how the key is built
 //nolint:gocognit,gocyclo,cyclop
-func (thisParam *IdentificationParameter) doBuildUniqueKey(ent *JsonEntity, currentPathValue string) (result string) {
+func (thisParam *IdentificationParameter) doBuildUniqueKey(ent *JsonEntity, currentPathValue string, trace *keyTrace) (result string) {
 	// handling the particular cases specificied in the "when"
 	if len(thisParam.When) > 0 {
 		for _, condition := range thisParam.When {
 			if condition.isVerifiedBy(ent) {
-				result = concatSeparatedString(condition.Name, sepPLUS, condition.doBuildUniqueKey(ent, currentPathValue))
+				conditionTrace := trace.add("when '%s' is '%s': verified", condition.Prop, condition.Is)
+				result = concatSeparatedString(condition.Name, sepPLUS, condition.doBuildUniqueKey(ent, currentPathValue, conditionTrace))
 
 				goto End
 			}
+
+			if trace != nil {
+				trace.add("when '%s' is '%s': not verified (%s = %v)", condition.Prop, condition.Is, condition.Prop, ent.values[condition.Prop])
+			}
 		}
 	}
 
 	// using the "use" if there's one
 	if len(thisParam.Use) > 0 {
+		useTrace := trace.add("_use [%s]", strings.Join(thisParam.Use, ", "))
+
 		for _, prop := range thisParam.Use {
-			result = concatSeparatedString(result, sepPLUS, thisParam.getStringValueFromObj(ent.values, prop))
+			value := thisParam.getStringValueFromObj(ent.values, prop)
+			result = concatSeparatedString(result, sepPLUS, value)
+
+			if useTrace != nil {
+				if _, present := ent.values[prop]; present {
+					useTrace.add("%s = '%s'", prop, value)
+				} else {
+					useTrace.add("%s is missing: '%s'", prop, value)
+				}
+			}
 		}
 
 		if !thisParam.isWithinWhen() && result == "" {
@@ -54,14 +72,16 @@ func (thisParam *IdentificationParameter) doBuildUniqueKey(ent *JsonEntity, curr
 		if nextIdParam.At == parentPATH { // we're looking back
 			// getting the origin of the current origin - we'll call it the "ancestor"
 			if ancestor := ent.parent; ancestor != nil {
-				result = concatSeparatedString(result, sepPLUS, nextIdParam.doBuildUniqueKey(ancestor, currentPathValue))
+				lookTrace := trace.add("look at '%s': the parent object", parentPATH)
+				result = concatSeparatedString(result, sepPLUS, nextIdParam.doBuildUniqueKey(ancestor, currentPathValue, lookTrace))
 			} else {
 				panic(fmt.Sprintf("No parent found with '%s' from '%s' (param = %s). Current obj = %v", parentPATH, currentPathValue, thisParam.toString(), ent))
 			}
 
 		} else if nextIdParam.At == currentPATH { // we're looking at our current object itself
 			//
-			result = concatSeparatedString(result, sepPLUS, nextIdParam.doBuildUniqueKey(ent, currentPathValue))
+			lookTrace := trace.add("look at '%s': the current object", currentPATH)
+			result = concatSeparatedString(result, sepPLUS, nextIdParam.doBuildUniqueKey(ent, currentPathValue, lookTrace))
 			//
 		} else {
 			// if we're not using the current object at path ".", then let's go deeper
@@ -69,13 +89,15 @@ func (thisParam *IdentificationParameter) doBuildUniqueKey(ent *JsonEntity, curr
 
 			case map[string]interface{}:
 				// we're "descending" into an object here
-				result = concatSeparatedString(result, sepPLUS, nextIdParam.doBuildUniqueKey(entityFrom(target, ent), currentPathValue))
+				lookTrace := trace.add("look at '%s': an object", nextIdParam.At)
+				result = concatSeparatedString(result, sepPLUS, nextIdParam.doBuildUniqueKey(entityFrom(target, ent), currentPathValue, lookTrace))
 
 			case []map[string]interface{}:
 				// now, we're building a key from an array of objects, hurraaay
 				values := []string{}
-				for _, targetItem := range target.([]map[string]interface{}) {
-					key := nextIdParam.doBuildUniqueKey(entityFrom(targetItem, ent), currentPathValue)
+				lookTrace := trace.add("look at '%s': %d objects", nextIdParam.At, len(target.([]map[string]interface{})))
+				for index, targetItem := range target.([]map[string]interface{}) {
+					key := nextIdParam.doBuildUniqueKey(entityFrom(targetItem, ent), currentPathValue, lookTrace.add("object #%d", index))
 					if key != "" || !nextIdParam.isWithinWhen() {
 						values = append(values, key)
 					}
@@ -89,8 +111,10 @@ func (thisParam *IdentificationParameter) doBuildUniqueKey(ent *JsonEntity, curr
 				if target == nil {
 					if ok { // the value was present
 						result = concatSeparatedString(result, sepPLUS, nextIdParam.At+"empty ??")
+						trace.add("look at '%s': null value", nextIdParam.At)
 					} else { // the value was missing
 						result = concatSeparatedString(result, sepPLUS, "("+nextIdParam.At+")")
+						trace.add("look at '%s': missing: '(%s)'", nextIdParam.At, nextIdParam.At)
 					}
 				} else {
 					panic(fmt.Errorf("Cannot handle the OBJECT (of type: %T) at path '%s' (which is part of this id param: %v) Full path: %s. Value = %v",
@@ -110,7 +134,9 @@ End:
 
 	// handling the increment
 	if thisParam.Incr {
-		result = thisParam.incrKey(ent.parent, result)
+		incrResult := thisParam.incrKey(ent.parent, result)
+		trace.add("incr: '%s' becomes '%s'", result, incrResult)
+		result = incrResult
 	}
 
 	// building an alias for this object ?
@@ -118,6 +144,8 @@ End:
 		thisParam.addAlias1(ent.values, currentPathValue)
 	}
 
+	trace.add("key: '%s'", result)
+
 	return
 }
 
@@ -133,8 +161,18 @@ func (thisParam *IdentificationParameter) getStringValueFromObj(obj map[string]i
 		//nolint:revive, gomnd
 		return strconv.FormatFloat(floatValue, 'f', 6, 64)
 
+	case json.Number:
+		// the numbers decoded exactly
+		return formatExactNumber(value.(json.Number))
+
 	case string:
-		return value.(string)
+		// the strings are normalized as configured for this prop
+		normalization := thisParam.normalization
+		if propParam := thisParam.For[prop]; propParam != nil {
+			normalization = propParam.normalization
+		}
+
+		return normalization.apply(value.(string))
 
 	case bool:
 		if value.(bool) {
@@ -145,8 +183,8 @@ func (thisParam *IdentificationParameter) getStringValueFromObj(obj map[string]i
 
 	case map[string]interface{}:
 		// a f*cked up case: we expect to get a tag's value, but if this tag unexpectedly contains attributes,
-		// then go creates a map for it, and stores the value with the "#text" key
-		return thisParam.getStringValueFromObj(value.(map[string]interface{}), "#text")
+		// then go creates a map for it, and stores the value with the "#text" key - or the one configured
+		return thisParam.getStringValueFromObj(value.(map[string]interface{}), thisParam.getXmlTextKey())
 
 	default:
 		// if we have a nil value at the intended path, we still use it
@@ -0,0 +1,424 @@
+package core
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+//------------------------------------------------------------------------------
+// Here is our own XML decoder, following the conventions of xml2map, but
+// with the elements and attributes named after their namespace URIs rather
+// than their prefixes, so that `ns1:order` and `o:order` are the same thing;
+// it also allocates a lot less, for the big XML files - see the benchmark
+//------------------------------------------------------------------------------
+
+// xmlElement : an element being decoded
+type xmlElement struct {
+	label    string                   // the element's name, as used in the decoded structure
+	values   map[string]interface{}   // the element's attributes - prefixed with "@" by default - and children, if any
+	elements bool                     // true if the element has child elements
+	attrKids bool                     // true if the element's attributes are decoded like child elements
+	text     strings.Builder          // the element's text
+	idParam  *IdentificationParameter // the element's own ID param, if any, for the per-path normalization settings
+	norm     *XmlNormalization        // how to normalize this element's content
+	mixed    []string                 // the element's text and children, in order, when keeping the mixed content
+	comments []string                 // the element's comments, when comparing them
+	pis      []string                 // the element's processing instructions, when comparing them
+}
+
+// xmlDecoder : what's needed to decode an XML document; since the documents can be huge, we try to allocate as little as possible
+type xmlDecoder struct {
+	options    *ComparisonOptions
+	norm       *XmlNormalization   // the default normalization settings
+	attrPrefix string              // the prefix of the attributes' names
+	textKey    string              // the key of the text of the elements having attributes or children
+	labels     map[xml.Name]string // the labels already computed for the elements
+	attrLabels map[xml.Name]string // the labels already computed for the attributes, prefix included
+	stack      []*xmlElement       // the elements being decoded
+	free       []*xmlElement       // the elements already decoded, which can be reused
+}
+
+// DecodeXml decodes an XML document, read from the given reader, with the given options - see decodeXml
+func DecodeXml(reader io.Reader, options *ComparisonOptions) (map[string]interface{}, error) {
+	return decodeXml(reader, options)
+}
+
+// decodeXml decodes an XML document, following the conventions of xml2map: an element is a string if it only has some text, else a map of its
+// attributes (prefixed with "@" by default), children, and text ("#text" by default); the repeated elements are slices. The content is
+// normalized along the way - the CDATA sections being just text, here
+func decodeXml(reader io.Reader, options *ComparisonOptions) (map[string]interface{}, error) {
+	decoder := &xmlDecoder{
+		options:    options,
+		norm:       options.getXmlNormalization(),
+		attrPrefix: options.getXmlAttrPrefix(),
+		textKey:    options.getXmlTextKey(),
+		labels:     map[xml.Name]string{},
+		attrLabels: map[xml.Name]string{},
+	}
+
+	return decoder.decode(xml.NewDecoder(reader))
+}
+
+func (thisDecoder *xmlDecoder) decode(decoder *xml.Decoder) (map[string]interface{}, error) {
+	for {
+		token, errToken := decoder.Token()
+		if errToken != nil {
+			if errors.Is(errToken, io.EOF) {
+				return nil, fmt.Errorf("no root element found")
+			}
+
+			return nil, errToken
+		}
+
+		switch typedToken := token.(type) {
+		case xml.StartElement:
+			thisDecoder.startElement(typedToken)
+
+		case xml.CharData:
+			if element := thisDecoder.current(); element != nil {
+				// the text mixed with child elements is not kept, unless asked to
+				if *element.norm.Mixed {
+					element.mixed = append(element.mixed, string(typedToken))
+				} else if element.elements {
+					continue
+				}
+
+				element.text.Write(typedToken)
+			}
+
+		case xml.Comment:
+			if element := thisDecoder.current(); element != nil && *element.norm.Comments {
+				element.comments = append(element.comments, element.norm.normalizeText(string(typedToken)))
+			}
+
+		case xml.ProcInst:
+			if element := thisDecoder.current(); element != nil && *element.norm.PIs {
+				element.pis = append(element.pis, element.norm.normalizeText(typedToken.Target+" "+string(typedToken.Inst)))
+			}
+
+		case xml.EndElement:
+			if root := thisDecoder.endElement(); root != nil {
+				return root, nil
+			}
+		}
+	}
+}
+
+// current returns the element being decoded, if any
+func (thisDecoder *xmlDecoder) current() *xmlElement {
+	if len(thisDecoder.stack) == 0 {
+		return nil
+	}
+
+	return thisDecoder.stack[len(thisDecoder.stack)-1]
+}
+
+// startElement starts decoding a new element, reusing a previous one if possible
+func (thisDecoder *xmlDecoder) startElement(start xml.StartElement) {
+	var element *xmlElement
+
+	if nbFree := len(thisDecoder.free); nbFree > 0 {
+		element, thisDecoder.free = thisDecoder.free[nbFree-1], thisDecoder.free[:nbFree-1]
+	} else {
+		element = &xmlElement{}
+	}
+
+	element.label = thisDecoder.getLabel(start.Name)
+
+	// the normalization settings can be overridden per path, and are inherited
+	if parent := thisDecoder.current(); parent == nil {
+		element.idParam = thisDecoder.options.IdParams.getXmlChildParam(element.label)
+		element.norm = thisDecoder.norm.override(element.idParam.getXmlNormalization())
+	} else {
+		element.idParam = parent.idParam.getXmlChildParam(element.label)
+		element.norm = parent.norm.override(element.idParam.getXmlNormalization())
+	}
+
+	for _, attr := range start.Attr {
+		// the namespace declarations are not data
+		if attr.Name.Space == xmlXMLNS || (attr.Name.Space == "" && attr.Name.Local == xmlXMLNS) {
+			continue
+		}
+
+		// the attributes can be equivalent to child elements
+		if thisDecoder.options.XmlAttrsAsChildren {
+			element.addChild(thisDecoder.getLabel(attr.Name), attr.Value, thisDecoder.textKey)
+			element.attrKids = true
+
+			continue
+		}
+
+		if element.values == nil {
+			element.values = make(map[string]interface{}, len(start.Attr))
+		}
+
+		element.values[thisDecoder.getAttrLabel(attr.Name)] = attr.Value
+	}
+
+	thisDecoder.stack = append(thisDecoder.stack, element)
+}
+
+// endElement ends the decoding of the current element, and returns the whole document if it was the root element
+func (thisDecoder *xmlDecoder) endElement() map[string]interface{} {
+	element := thisDecoder.stack[len(thisDecoder.stack)-1]
+	thisDecoder.stack = thisDecoder.stack[:len(thisDecoder.stack)-1]
+
+	// we're done with the root element
+	if len(thisDecoder.stack) == 0 {
+		return map[string]interface{}{element.label: element.getValue(thisDecoder.textKey)}
+	}
+
+	parent := thisDecoder.stack[len(thisDecoder.stack)-1]
+	parent.addChild(element.label, element.getValue(thisDecoder.textKey), thisDecoder.textKey)
+
+	if *parent.norm.Mixed {
+		parent.mixed = append(parent.mixed, "<"+element.label+"/>")
+	} else if !parent.elements {
+		// no need to keep the text that came before the 1st child element
+		parent.text.Reset()
+	}
+
+	parent.elements = true
+
+	// the element can now be reused
+	*element = xmlElement{}
+	thisDecoder.free = append(thisDecoder.free, element)
+
+	return nil
+}
+
+// getLabel returns the label for an element's name, computing it once
+func (thisDecoder *xmlDecoder) getLabel(name xml.Name) string {
+	label, found := thisDecoder.labels[name]
+	if !found {
+		label = thisDecoder.options.getXmlLabel(name)
+		thisDecoder.labels[name] = label
+	}
+
+	return label
+}
+
+// getAttrLabel returns the label for an attribute's name, prefix included, computing it once
+func (thisDecoder *xmlDecoder) getAttrLabel(name xml.Name) string {
+	label, found := thisDecoder.attrLabels[name]
+	if !found {
+		label = thisDecoder.attrPrefix + thisDecoder.options.getXmlLabel(name)
+		thisDecoder.attrLabels[name] = label
+	}
+
+	return label
+}
+
+// getValue returns the decoded value for this element, its text being stored with the given key if needed
+func (thisElement *xmlElement) getValue(textKey string) interface{} {
+	// just some text
+	if thisElement.values == nil && thisElement.comments == nil && thisElement.pis == nil {
+		return thisElement.norm.normalizeText(thisElement.text.String())
+	}
+
+	value := thisElement.values
+	if value == nil {
+		value = map[string]interface{}{}
+	}
+
+	// like xml2map, we do not keep the text mixed with child elements - unless asked to, in which case it's kept with the children's positions
+	if !thisElement.elements {
+		// the attributes decoded like child elements have no text between them, so there's no empty text to keep
+		if text := thisElement.norm.normalizeText(thisElement.text.String()); text != "" || !thisElement.attrKids {
+			value[textKey] = text
+		}
+	} else if *thisElement.norm.Mixed && strings.TrimSpace(thisElement.text.String()) != "" {
+		value[xmlMIXED] = thisElement.norm.normalizeText(strings.Join(thisElement.mixed, ""))
+	}
+
+	if thisElement.comments != nil {
+		value[xmlCOMMENT] = getXmlTexts(thisElement.comments)
+	}
+
+	if thisElement.pis != nil {
+		value[xmlPI] = getXmlTexts(thisElement.pis)
+	}
+
+	return value
+}
+
+// getXmlTexts returns the given texts as a string if there's only 1, else as a slice, as for the repeated elements
+func getXmlTexts(texts []string) interface{} {
+	if len(texts) == 1 {
+		return texts[0]
+	}
+
+	return texts
+}
+
+// addChild adds a child element's value to this element; the repeated elements are gathered into slices, the strings being turned
+// into maps, with their text at the given key, if mixed with maps
+func (thisElement *xmlElement) addChild(label string, value interface{}, textKey string) {
+	if thisElement.values == nil {
+		thisElement.values = map[string]interface{}{}
+	}
+
+	existing, exists := thisElement.values[label]
+	if !exists {
+		thisElement.values[label] = value
+
+		return
+	}
+
+	// repeated strings
+	if text, isText := value.(string); isText {
+		switch typedExisting := existing.(type) {
+		case string:
+			thisElement.values[label] = []string{typedExisting, text}
+
+			return
+		case []string:
+			thisElement.values[label] = append(typedExisting, text)
+
+			return
+		}
+	}
+
+	// repeated maps - a string being a map with just some text, here
+	maps := []map[string]interface{}{}
+
+	switch typedExisting := existing.(type) {
+	case string:
+		maps = append(maps, map[string]interface{}{textKey: typedExisting})
+	case []string:
+		for _, text := range typedExisting {
+			maps = append(maps, map[string]interface{}{textKey: text})
+		}
+	case map[string]interface{}:
+		maps = append(maps, typedExisting)
+	case []map[string]interface{}:
+		maps = typedExisting
+	}
+
+	switch typedValue := value.(type) {
+	case string:
+		maps = append(maps, map[string]interface{}{textKey: typedValue})
+	case map[string]interface{}:
+		maps = append(maps, typedValue)
+	}
+
+	thisElement.values[label] = maps
+}
+
+//------------------------------------------------------------------------------
+// Naming the elements and attributes
+//------------------------------------------------------------------------------
+
+// getXmlLabel returns the name to use for an element or attribute: its local name if it has no namespace, else prefixed with the canonical
+// prefix of its namespace if there's one, or else with the namespace URI itself, e.g. "{urn:orders}order"
+func (thisComp *ComparisonOptions) getXmlLabel(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+
+	if prefix, hasPrefix := thisComp.Xmlns[name.Space]; hasPrefix {
+		return concatSeparatedString(prefix, ":", name.Local)
+	}
+
+	return "{" + name.Space + "}" + name.Local
+}
+
+// getXmlChildParam returns the ID param of a child element, declared with its label, or its local name
+func (thisParam *IdentificationParameter) getXmlChildParam(label string) *IdentificationParameter {
+	if thisParam == nil {
+		return nil
+	}
+
+	if childParam := thisParam.For[label]; childParam != nil {
+		return childParam
+	}
+
+	return thisParam.For[getLocalName(label)]
+}
+
+// getXmlNormalization returns the normalization settings given for this ID param's path, if any
+func (thisParam *IdentificationParameter) getXmlNormalization() *XmlNormalization {
+	if thisParam == nil {
+		return nil
+	}
+
+	return thisParam.Xml
+}
+
+// getXmlAttrPrefix returns the prefix of the attributes' names: none if they're decoded like child elements, "@" by default
+func (thisComp *ComparisonOptions) getXmlAttrPrefix() string {
+	if thisComp.XmlAttrsAsChildren {
+		return ""
+	}
+
+	if thisComp.XmlAttrPrefix == "" {
+		return xmlATTR
+	}
+
+	return thisComp.XmlAttrPrefix
+}
+
+// getXmlTextKey returns the key of the text of the elements having attributes or children: "#text" by default
+func (thisComp *ComparisonOptions) getXmlTextKey() string {
+	if thisComp.XmlTextKey == "" {
+		return xmlTEXT
+	}
+
+	return thisComp.XmlTextKey
+}
+
+// getXmlTextKey returns the key of the XML text to use with this ID param - which is known by the root ID param
+func (thisParam *IdentificationParameter) getXmlTextKey() string {
+	if thisParam == nil {
+		return xmlTEXT
+	}
+
+	root := thisParam
+	for root.parent != nil {
+		root = root.parent
+	}
+
+	if root.xmlTextKey == "" {
+		return xmlTEXT
+	}
+
+	return root.xmlTextKey
+}
+
+// getXmlnsFromString reads the canonical prefixes for the namespace URIs, given as "prefix=uri" separated by commas, or as a file listing them
+func (thisComp *ComparisonOptions) getXmlnsFromString() map[string]string {
+	if thisComp.XmlnsString == "" {
+		return nil
+	}
+
+	xmlns := map[string]string{}
+
+	for _, declaration := range strings.FieldsFunc(getStringOrFileContent(thisComp.XmlnsString), func(r rune) bool { return r == ',' || r == '\n' || r == '\r' }) {
+		if declaration = strings.TrimSpace(declaration); declaration == "" {
+			continue
+		}
+
+		parts := strings.SplitN(declaration, "=", 2) //nolint:gomnd
+		if len(parts) != 2 || strings.TrimSpace(parts[1]) == "" {
+			panic(fmt.Errorf("not a valid namespace declaration: '%s'; expected: 'prefix=uri'", declaration))
+		}
+
+		xmlns[strings.TrimSpace(parts[1])] = strings.TrimSpace(parts[0])
+	}
+
+	return xmlns
+}
+
+// getLocalName returns the name of an element or attribute without its namespace, be it a prefix or a URI
+func getLocalName(label string) string {
+	if strings.HasPrefix(label, "{") {
+		if end := strings.Index(label, "}"); end > 0 {
+			return label[end+1:]
+		}
+	}
+
+	return label[strings.LastIndex(label, ":")+1:]
+}
@@ -0,0 +1,52 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// BenchmarkDecodeXml decodes generated documents of increasing sizes; run with: go test ./core -run '^$' -bench DecodeXml -benchmem
+func BenchmarkDecodeXml(b *testing.B) {
+	//nolint:gomnd
+	for nbOrders := 100; nbOrders <= 10000; nbOrders *= 10 {
+		data := generateXmlOrders(nbOrders)
+
+		b.Run(strconv.Itoa(nbOrders)+"-orders", func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(data)))
+
+			options := &ComparisonOptions{}
+
+			for i := 0; i < b.N; i++ {
+				if _, errDecode := decodeXml(bytes.NewReader(data), options); errDecode != nil {
+					b.Fatal(errDecode)
+				}
+			}
+		})
+	}
+}
+
+// generateXmlOrders builds an XML document with the given number of orders, each one having a few attributes and lines
+func generateXmlOrders(nbOrders int) []byte {
+	var builder strings.Builder
+
+	builder.WriteString("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<orders xmlns=\"urn:orders\">\n")
+
+	for i := 0; i < nbOrders; i++ {
+		fmt.Fprintf(&builder, "  <order id=\"%d\" status=\"open\">\n    <customer>Customer #%d</customer>\n", i, i%97)
+
+		//nolint:gomnd
+		for j := 0; j < 3; j++ {
+			fmt.Fprintf(&builder, "    <line sku=\"SKU-%d\">\n      <qty>%d</qty>\n      <price currency=\"EUR\">%d.50</price>\n    </line>\n", j, j+1, i%1000)
+		}
+
+		builder.WriteString("  </order>\n")
+	}
+
+	builder.WriteString("</orders>\n")
+
+	return []byte(builder.String())
+}
@@ -0,0 +1,32 @@
+//go:build xml2map
+// +build xml2map
+
+package core
+
+import (
+	"bytes"
+	"strconv"
+	"testing"
+
+	"github.com/sbabiv/xml2map"
+)
+
+// BenchmarkDecodeXmlWithXml2map decodes the same documents as BenchmarkDecodeXml, with xml2map, which we used up to now, to compare the 2 decoders;
+// run with: go test ./core -tags xml2map -run '^$' -bench DecodeXml -benchmem
+func BenchmarkDecodeXmlWithXml2map(b *testing.B) {
+	//nolint:gomnd
+	for nbOrders := 100; nbOrders <= 10000; nbOrders *= 10 {
+		data := generateXmlOrders(nbOrders)
+
+		b.Run(strconv.Itoa(nbOrders)+"-orders", func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(data)))
+
+			for i := 0; i < b.N; i++ {
+				if _, errDecode := xml2map.NewDecoder(bytes.NewReader(data)).Decode(); errDecode != nil {
+					b.Fatal(errDecode)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+//------------------------------------------------------------------------------
+// Here we normalize the XML content before comparing it, since the producers
+// differ in pretty-printing, and may or may not have comments, PIs, or text
+// mixed with the elements
+//------------------------------------------------------------------------------
+
+// the ways to handle the whitespace in the XML text nodes
+const (
+	xmlSpaceTRIM     = "trim"     // the leading and trailing whitespace is removed - the default
+	xmlSpaceCOLLAPSE = "collapse" // also, any sequence of whitespace characters becomes 1 space
+	xmlSpacePRESERVE = "preserve" // the whitespace is kept as is
+)
+
+// the keys of the XML content which is not made of elements, attributes or text
+const (
+	xmlCOMMENT = "#comment" // the comments of an element
+	xmlPI      = "#pi"      // the processing instructions within an element, as "target instruction"
+	xmlMIXED   = "#mixed"   // the text of an element mixed with its child elements, in order, the children being written as "<name/>"
+)
+
+// XmlNormalization : how to normalize the XML content before comparing it; these settings are given with the options, and can be
+// overridden per path in the ID params
+type XmlNormalization struct {
+	Whitespace string `json:"whitespace,omitempty"` // how to handle the whitespace in the text nodes: "trim" (by default), "collapse" or "preserve"
+	Comments   *bool  `json:"comments,omitempty"`   // if true, then the comments are compared too, as "#comment"
+	PIs        *bool  `json:"pis,omitempty"`        // if true, then the processing instructions are compared too, as "#pi"
+	Mixed      *bool  `json:"mixed,omitempty"`      // if true, then the text mixed with child elements is kept, in order, as "#mixed"
+}
+
+// getXmlNormalization returns the normalization settings given with the options
+func (thisComp *ComparisonOptions) getXmlNormalization() *XmlNormalization {
+	whitespace := thisComp.XmlSpace
+	if whitespace == "" {
+		whitespace = xmlSpaceTRIM
+	}
+
+	comments, pis, mixed := thisComp.XmlComments, thisComp.XmlPIs, thisComp.XmlMixed
+
+	return &XmlNormalization{Whitespace: whitespace, Comments: &comments, PIs: &pis, Mixed: &mixed}
+}
+
+// checkXmlSpace makes sure the given way to handle the whitespace is known
+func checkXmlSpace(whitespace string) {
+	switch whitespace {
+	case "", xmlSpaceTRIM, xmlSpaceCOLLAPSE, xmlSpacePRESERVE:
+	default:
+		panic(fmt.Errorf("unknown way to handle the XML whitespace: '%s'; expected: '%s', '%s' or '%s'", whitespace,
+			xmlSpaceTRIM, xmlSpaceCOLLAPSE, xmlSpacePRESERVE))
+	}
+}
+
+// override returns these settings, overridden with the given ones, if any
+func (thisNorm *XmlNormalization) override(overriding *XmlNormalization) *XmlNormalization {
+	if overriding == nil {
+		return thisNorm
+	}
+
+	result := *thisNorm
+
+	if overriding.Whitespace != "" {
+		result.Whitespace = overriding.Whitespace
+	}
+
+	if overriding.Comments != nil {
+		result.Comments = overriding.Comments
+	}
+
+	if overriding.PIs != nil {
+		result.PIs = overriding.PIs
+	}
+
+	if overriding.Mixed != nil {
+		result.Mixed = overriding.Mixed
+	}
+
+	return &result
+}
+
+// normalizeText handles the whitespace of some text
+func (thisNorm *XmlNormalization) normalizeText(text string) string {
+	switch thisNorm.Whitespace {
+	case xmlSpacePRESERVE:
+		return text
+	case xmlSpaceCOLLAPSE:
+		return strings.Join(strings.Fields(text), " ")
+	}
+
+	return strings.TrimSpace(text)
+}